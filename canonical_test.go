@@ -0,0 +1,98 @@
+package gnfmt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	assert := assert.New(t)
+	enc := GNjson{Canonical: true, Pretty: true}
+	tests := []struct {
+		msg    string
+		input  any
+		output string
+	}{
+		{
+			msg: "rfc8785 sample",
+			input: json.RawMessage(`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`),
+			output: `{"literals":[null,true,false],` +
+				`"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+				`"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			msg: "rfc8785 sorting",
+			input: json.RawMessage(`{
+  "\u20ac": "Euro Sign",
+  "\r": "Carriage Return",
+  "\ufb33": "Hebrew Letter Dalet With Dagesh",
+  "1": "One",
+  "\ud83d\ude00": "Emoji: Grinning Face",
+  "\u0080": "Control",
+  "\u00f6": "Latin Small Letter O With Diaeresis"
+}`),
+			output: `{"\r":"Carriage Return","1":"One","` + "\u0080" +
+				`":"Control","ö":"Latin Small Letter O With Diaeresis",` +
+				`"€":"Euro Sign","😀":"Emoji: Grinning Face",` +
+				`"` + "\ufb33" + `":"Hebrew Letter Dalet With Dagesh"}`,
+		},
+		{
+			msg: "struct",
+			input: struct {
+				Name  string            `json:"name"`
+				Year  int               `json:"year"`
+				Score float64           `json:"score"`
+				Tags  map[string]string `json:"tags"`
+			}{
+				Name:  "Bubo & <bubo>",
+				Year:  1758,
+				Score: 0.1,
+				Tags:  map[string]string{"b": "2", "a": "1"},
+			},
+			output: `{"name":"Bubo & <bubo>","score":0.1,` +
+				`"tags":{"a":"1","b":"2"},"year":1758}`,
+		},
+	}
+
+	for _, v := range tests {
+		res, err := enc.Encode(v.input)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.output, string(res), v.msg)
+	}
+}
+
+func TestCanonicalNumbers(t *testing.T) {
+	assert := assert.New(t)
+	enc := GNjson{Canonical: true}
+	tests := []struct {
+		input  float64
+		output string
+	}{
+		{0, "0"},
+		{-0.0, "0"},
+		{1, "1"},
+		{-1.5, "-1.5"},
+		{1e20, "100000000000000000000"},
+		{1e21, "1e+21"},
+		{1e-6, "0.000001"},
+		{1e-7, "1e-7"},
+		{5e-324, "5e-324"},
+		{1.7976931348623157e308, "1.7976931348623157e+308"},
+		{9007199254740992, "9007199254740992"},
+		{295147905179352830000, "295147905179352830000"},
+	}
+
+	for _, v := range tests {
+		res, err := enc.Encode(v.input)
+		assert.Nil(err)
+		assert.Equal(v.output, string(res))
+	}
+}
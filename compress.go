@@ -0,0 +1,268 @@
+package gnfmt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compression sets an algorithm used to compress encoded data.
+type Compression int
+
+const (
+	// NoCompression leaves data as is.
+	NoCompression Compression = iota
+
+	// Gzip compresses data with gzip (RFC 1952).
+	Gzip
+
+	// Xz compresses data with xz (LZMA2).
+	Xz
+
+	// Zstd compresses data with Zstandard (RFC 8878).
+	Zstd
+)
+
+var compressionMap = map[Compression]string{
+	NoCompression: "none",
+	Gzip:          "gzip",
+	Xz:            "xz",
+	Zstd:          "zstd",
+}
+
+// String representation of a compression algorithm.
+func (c Compression) String() string {
+	if res, ok := compressionMap[c]; ok {
+		return res
+	}
+	return fmt.Sprintf("unknown compression %d", int(c))
+}
+
+// MinLevel and MaxLevel define the range of compression levels. Lower
+// levels are faster, higher levels give better compression. Zero level
+// means the default level of an algorithm.
+const (
+	MinLevel = 1
+	MaxLevel = 9
+)
+
+// Compressed wraps any Encoder and compresses the results of its
+// encoding. Decode decompresses the input before passing it to the
+// wrapped Encoder.
+type Compressed struct {
+	// Encoder converts objects to bytes and back.
+	Encoder Encoder
+
+	// Compression is the algorithm used for compression.
+	Compression Compression
+
+	// Level of compression from MinLevel to MaxLevel. If it is zero,
+	// the default level of the algorithm is used.
+	Level int
+}
+
+// Encode encodes an object with the wrapped Encoder and compresses the
+// result.
+func (c Compressed) Encode(input any) ([]byte, error) {
+	if c.Encoder == nil {
+		return nil, errors.New("compressed: encoder is not set")
+	}
+	res, err := c.Encoder.Encode(input)
+	if err != nil {
+		return nil, err
+	}
+	return compress(res, c.Compression, c.Level)
+}
+
+// Decode decompresses the input and decodes it into output with the
+// wrapped Encoder.
+func (c Compressed) Decode(input []byte, output any) error {
+	if c.Encoder == nil {
+		return errors.New("compressed: encoder is not set")
+	}
+	res, err := decompress(input, c.Compression)
+	if err != nil {
+		return err
+	}
+	return c.Encoder.Decode(res, output)
+}
+
+// compress compresses data with the given algorithm and level.
+func compress(data []byte, c Compression, level int) ([]byte, error) {
+	if level < 0 || level > MaxLevel {
+		return nil, fmt.Errorf(
+			"compression level %d is out of range %d-%d", level, MinLevel, MaxLevel,
+		)
+	}
+
+	if c == Zstd {
+		enc, err := zstdEncoder(level)
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(data, nil), nil
+	}
+
+	var buf bytes.Buffer
+	w, err := newCompressWriter(&buf, c, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress decompresses data compressed by the given algorithm.
+func decompress(data []byte, c Compression) ([]byte, error) {
+	if c == Zstd {
+		dec, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(data, nil)
+	}
+
+	r, err := newDecompressReader(bytes.NewReader(data), c)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// newCompressWriter creates a writer that compresses everything
+// written to it with the given algorithm and level. The writer must
+// be closed to flush the compressed data.
+func newCompressWriter(
+	w io.Writer,
+	c Compression,
+	level int,
+) (io.WriteCloser, error) {
+	switch c {
+	case NoCompression:
+		return nopWriteCloser{w}, nil
+	case Gzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case Xz:
+		cfg := xz.WriterConfig{}
+		if level > 0 {
+			cfg.DictCap = xzDictCaps[level-1]
+		}
+		return cfg.NewWriter(w)
+	case Zstd:
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstdLevel(level)))
+	default:
+		return nil, fmt.Errorf("unknown compression %d", int(c))
+	}
+}
+
+// newDecompressReader creates a reader that decompresses data read from
+// r with the given algorithm.
+func newDecompressReader(r io.Reader, c Compression) (io.ReadCloser, error) {
+	switch c {
+	case NoCompression:
+		return io.NopCloser(r), nil
+	case Gzip:
+		return gzip.NewReader(r)
+	case Xz:
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(xr), nil
+	case Zstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %d", int(c))
+	}
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	xzMagic   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// detectCompression finds out the compression algorithm by the magic bytes
+// at the start of data.
+func detectCompression(data []byte) Compression {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		return Gzip
+	case bytes.HasPrefix(data, xzMagic):
+		return Xz
+	case bytes.HasPrefix(data, zstdMagic):
+		return Zstd
+	default:
+		return NoCompression
+	}
+}
+
+// xzDictCaps are dictionary sizes for levels from 1 to 9.
+var xzDictCaps = []int{
+	1 << 16, 1 << 18, 1 << 19, 1 << 20, 1 << 21,
+	1 << 22, 1 << 23, 1 << 24, 1 << 25,
+}
+
+// zstdLevel maps levels from 1 to 9 to zstd speed settings.
+func zstdLevel(level int) zstd.EncoderLevel {
+	switch {
+	case level == 0:
+		return zstd.SpeedDefault
+	case level <= 2:
+		return zstd.SpeedFastest
+	case level <= 5:
+		return zstd.SpeedDefault
+	case level <= 7:
+		return zstd.SpeedBetterCompression
+	default:
+		return zstd.SpeedBestCompression
+	}
+}
+
+var (
+	zstdEncoders sync.Map
+	zstdDecoder  = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+)
+
+// zstdEncoder returns a zstd encoder for the given level. Encoders are
+// created once and reused, as they are expensive to create and safe for
+// concurrent use with EncodeAll.
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	zl := zstdLevel(level)
+	if enc, ok := zstdEncoders.Load(zl); ok {
+		return enc.(*zstd.Encoder), nil
+	}
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zl))
+	if err != nil {
+		return nil, err
+	}
+	res, _ := zstdEncoders.LoadOrStore(zl, enc)
+	return res.(*zstd.Encoder), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,63 @@
+package gnfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestCompressed(t *testing.T) {
+	assert := assert.New(t)
+	obj := version{
+		Version: "v10.10.10",
+		Build:   strings.Repeat("today ", 100),
+	}
+
+	encs := []Encoder{GNjson{}, GNgob{}, GNmsgpack{}}
+	comps := []Compression{NoCompression, Gzip, Xz, Zstd}
+	levels := []int{0, MinLevel, 5, MaxLevel}
+
+	for _, e := range encs {
+		plain, err := e.Encode(obj)
+		assert.Nil(err)
+		for _, c := range comps {
+			for _, l := range levels {
+				msg := c.String()
+				enc := Compressed{Encoder: e, Compression: c, Level: l}
+				res, err := enc.Encode(obj)
+				assert.Nil(err, msg)
+				if c == NoCompression {
+					assert.Equal(plain, res, msg)
+				} else {
+					assert.Less(len(res), len(plain), msg)
+				}
+
+				var ver version
+				err = enc.Decode(res, &ver)
+				assert.Nil(err, msg)
+				assert.Equal(obj, ver, msg)
+			}
+		}
+	}
+}
+
+func TestCompressedErr(t *testing.T) {
+	assert := assert.New(t)
+	obj := version{Version: "v10.10.10"}
+
+	_, err := Compressed{Compression: Gzip}.Encode(obj)
+	assert.NotNil(err)
+
+	_, err = Compressed{Encoder: GNjson{}, Compression: Gzip, Level: 10}.Encode(obj)
+	assert.NotNil(err)
+
+	_, err = Compressed{Encoder: GNjson{}, Compression: Compression(42)}.Encode(obj)
+	assert.NotNil(err)
+
+	var ver version
+	err = Compressed{Encoder: GNjson{}, Compression: Zstd}.Decode([]byte("{}"), &ver)
+	assert.NotNil(err)
+}
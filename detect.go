@@ -0,0 +1,208 @@
+package gnfmt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// sniffLen is the maximum number of bytes DetectFormat analyzes.
+const sniffLen = 8192
+
+// sniffLines is the maximum number of lines used to detect a separator
+// of CSV/TSV data.
+const sniffLines = 10
+
+// ErrUnknownFormat means that a format cannot be detected.
+var ErrUnknownFormat = errors.New("cannot detect format")
+
+// compressionExts are extensions of compressed files that are ignored
+// when a format is detected from a file name.
+var compressionExts = map[string]struct{}{
+	".gz": {}, ".gzip": {}, ".xz": {}, ".zst": {}, ".zstd": {},
+}
+
+// FormatFromPath detects a format by the extension of a file. Extensions
+// of compressed files are ignored, so "out.tsv.gz" is detected as TSV.
+// Extensions are taken from the format registry. If several formats share
+// the same extension, the one registered first is returned (".json"
+// gives CompactJSON).
+func FormatFromPath(path string) (Format, error) {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	if _, ok := compressionExts[strings.ToLower(ext)]; ok {
+		base = strings.TrimSuffix(base, ext)
+		ext = filepath.Ext(base)
+	}
+
+	if ext != "" {
+		if f, ok := formatByExt(ext); ok {
+			return f, nil
+		}
+	}
+	return FormatNone, fmt.Errorf("%w from path '%s'", ErrUnknownFormat, path)
+}
+
+// DetectFormat analyzes the first kilobytes of data and detects if it is
+// CSV, TSV, CompactJSON, PrettyJSON or JSONL. Data compressed by gzip,
+// xz or zstd are decompressed before the analysis.
+//
+// JSON with the first document on one line is CompactJSON, if several
+// lines contain one document each, it is JSONL. JSON that spans several
+// lines is PrettyJSON. For other data the most frequent separator in the
+// first line decides between CSV and TSV.
+func DetectFormat(data []byte) (Format, error) {
+	data, truncated := sniffData(data)
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.TrimLeft(data, " \t\r\n")
+	if len(data) == 0 {
+		return FormatNone, fmt.Errorf("%w: no data", ErrUnknownFormat)
+	}
+
+	if data[0] == '{' || data[0] == '[' {
+		return detectJSON(data), nil
+	}
+
+	if f := detectDelimited(data, truncated); f != FormatNone {
+		return f, nil
+	}
+	return FormatNone, ErrUnknownFormat
+}
+
+// sniffData returns the beginning of data, decompressing it if needed.
+// The second returned value is true if the result does not include
+// the end of data.
+func sniffData(data []byte) ([]byte, bool) {
+	c := detectCompression(data)
+	if c == NoCompression {
+		return data[:min(len(data), sniffLen)], len(data) > sniffLen
+	}
+
+	r, err := newDecompressReader(bytes.NewReader(data), c)
+	if err != nil {
+		return nil, false
+	}
+	defer r.Close()
+
+	// data might be a truncated beginning of a file, so errors are
+	// ignored and we use everything that was decompressed.
+	res := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, res)
+	truncated := err == nil ||
+		(!errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF))
+	return res[:n], truncated
+}
+
+// detectJSON decides which of JSON formats is used. Data starts with
+// '{' or '['.
+func detectJSON(data []byte) Format {
+	first, rest, found := bytes.Cut(data, []byte("\n"))
+	if !found {
+		// one line, probably truncated
+		return CompactJSON
+	}
+	if !jsoniter.Valid(bytes.TrimSpace(first)) {
+		return PrettyJSON
+	}
+
+	rest = bytes.TrimLeft(rest, " \t\r\n")
+	if len(rest) > 0 && (rest[0] == '{' || rest[0] == '[') {
+		return JSONL
+	}
+	return CompactJSON
+}
+
+// detectDelimited checks the first records of data for tabs and commas
+// outside of quotes. The separator that is found more often in the first
+// record and occurs in all complete records wins. If data is truncated,
+// its last record is ignored.
+func detectDelimited(data []byte, truncated bool) Format {
+	recs := delimitedRecords(string(data))
+	if len(recs) > 1 && truncated {
+		// the last record might be truncated
+		recs = recs[:len(recs)-1]
+	}
+	recs = recs[:min(len(recs), sniffLines)]
+
+	var tabs, commas int
+	for i, rec := range recs {
+		if rec.blank {
+			continue
+		}
+		if i == 0 {
+			tabs, commas = rec.tabs, rec.commas
+			continue
+		}
+		if rec.tabs == 0 {
+			tabs = 0
+		}
+		if rec.commas == 0 {
+			commas = 0
+		}
+	}
+
+	switch {
+	case tabs > 0 && tabs >= commas:
+		return TSV
+	case commas > 0:
+		return CSV
+	default:
+		return FormatNone
+	}
+}
+
+// separatorCount keeps the number of tabs and commas in a record of
+// CSV/TSV data.
+type separatorCount struct {
+	tabs, commas int
+	blank        bool
+}
+
+// delimitedRecords splits data into records and counts tabs and commas
+// that are not inside double quotes. A quote opens a quoted field only
+// at the start of a field, new lines inside of quoted fields do not end
+// records. The last element is the text after the last new line.
+func delimitedRecords(data string) []separatorCount {
+	var res []separatorCount
+	cur := separatorCount{blank: true}
+	var quoted bool
+	fieldStart := true
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if quoted {
+			if c == '"' {
+				if i+1 < len(data) && data[i+1] == '"' {
+					// escaped quote
+					i++
+					continue
+				}
+				quoted = false
+			}
+			continue
+		}
+
+		switch c {
+		case '\n':
+			res = append(res, cur)
+			cur = separatorCount{blank: true}
+			fieldStart = true
+			continue
+		case '"':
+			quoted = fieldStart
+		case '\t':
+			cur.tabs++
+		case ',':
+			cur.commas++
+		}
+		if c != ' ' && c != '\t' && c != '\r' {
+			cur.blank = false
+		}
+		fieldStart = c == '\t' || c == ','
+	}
+	return append(res, cur)
+}
@@ -0,0 +1,111 @@
+package gnfmt_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		path   string
+		format Format
+		errNil bool
+	}{
+		{"out.csv", CSV, true},
+		{"/tmp/OUT.TSV", TSV, true},
+		{"out.tsv.gz", TSV, true},
+		{"data/names.json.zst", CompactJSON, true},
+		{"names.jsonl.xz", JSONL, true},
+		{"names.ndjson", JSONL, true},
+		{"config.yml", YAML, true},
+		{"names.gz", FormatNone, false},
+		{"names", FormatNone, false},
+		{"names.txt", FormatNone, false},
+	}
+
+	for _, v := range tests {
+		f, err := FormatFromPath(v.path)
+		assert.Equal(v.format, f, v.path)
+		assert.Equal(v.errNil, err == nil, v.path)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg    string
+		data   string
+		format Format
+		errNil bool
+	}{
+		{"compact", `{"a":1,"b":[1,2]}`, CompactJSON, true},
+		{"compact nl", "\xef\xbb\xbf  [1,2,3]\n", CompactJSON, true},
+		{"pretty", "{\n  \"a\": 1\n}\n", PrettyJSON, true},
+		{"pretty ary", "[\n  1,\n  2\n]", PrettyJSON, true},
+		{"jsonl", "{\"a\":1}\n{\"a\":2}\n", JSONL, true},
+		{"csv", "id,name\n1,\"Bubo\tbubo\"\n", CSV, true},
+		{"tsv", "id\tname\n1\tBubo, bubo\n", TSV, true},
+		{"csv multiline", "a,b\n\"x\ny\",z\n", CSV, true},
+		{"csv quotes", "a,b\n\"x \"\"1\"\"\ny\",z\n", CSV, true},
+		{"tsv inner quote", "id\tname\n1\tAus \"bus\n2\tCus\n", TSV, true},
+		{"tsv one column", "id\n1\n", FormatNone, false},
+		{"text", "just some text", FormatNone, false},
+		{"empty", " \n ", FormatNone, false},
+	}
+
+	for _, v := range tests {
+		f, err := DetectFormat([]byte(v.data))
+		assert.Equal(v.format, f, v.msg)
+		assert.Equal(v.errNil, err == nil, v.msg)
+	}
+}
+
+func TestDetectFormatTruncated(t *testing.T) {
+	assert := assert.New(t)
+	line := strings.Repeat("a", 995) + "\tb\n"
+	// leading whitespace is trimmed, and the truncated last line
+	// without a tab must still be ignored.
+	data := "\n\n\n" + strings.Repeat(line, 20)
+	f, err := DetectFormat([]byte(data))
+	assert.Nil(err)
+	assert.Equal(TSV, f)
+}
+
+func TestDetectFormatCompressed(t *testing.T) {
+	assert := assert.New(t)
+	data, err := os.ReadFile(filepath.Join("testdata", "test.tsv"))
+	assert.Nil(err)
+
+	// Compressed wraps an Encoder, a string encoder keeps data as is.
+	for _, c := range []Compression{Gzip, Xz, Zstd} {
+		enc := Compressed{Encoder: rawEncoder{}, Compression: c}
+		res, err := enc.Encode(string(data))
+		assert.Nil(err)
+		f, err := DetectFormat(res)
+		assert.Nil(err, c.String())
+		assert.Equal(TSV, f, c.String())
+	}
+
+	long := "{\"a\":\"" + strings.Repeat("a", 10_000) + "\"}"
+	f, err := DetectFormat([]byte(long))
+	assert.Nil(err)
+	assert.Equal(CompactJSON, f)
+}
+
+type rawEncoder struct{}
+
+func (rawEncoder) Encode(input any) ([]byte, error) {
+	return []byte(input.(string)), nil
+}
+
+func (rawEncoder) Decode(input []byte, output any) error {
+	*output.(*string) = string(input)
+	return nil
+}
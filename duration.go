@@ -0,0 +1,284 @@
+package gnfmt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationStyle determines how units of a duration are written.
+type DurationStyle int
+
+const (
+	// ShortStyle uses abbreviations of units, for example "2h 7m".
+	ShortStyle DurationStyle = iota
+
+	// LongStyle uses full names of units, for example "2 hours 7 minutes".
+	LongStyle
+)
+
+// Day is a duration of 24 hours, it is the largest unit used by
+// DurationFormatter.
+const Day = 24 * time.Hour
+
+// durationUnit describes a unit of time, its names for output and names
+// accepted by ParseDuration.
+type durationUnit struct {
+	size         time.Duration
+	short        string
+	long, plural string
+	aliases      []string
+}
+
+// durationUnits are sorted from the largest to the smallest unit.
+var durationUnits = []durationUnit{
+	{Day, "d", "day", "days", nil},
+	{time.Hour, "h", "hour", "hours", []string{"hr", "hrs"}},
+	{time.Minute, "m", "minute", "minutes", []string{"min", "mins"}},
+	{time.Second, "s", "second", "seconds", []string{"sec", "secs"}},
+	{time.Millisecond, "ms", "millisecond", "milliseconds", nil},
+	{time.Microsecond, "µs", "microsecond", "microseconds", []string{"us", "μs"}},
+	{time.Nanosecond, "ns", "nanosecond", "nanoseconds", nil},
+}
+
+// DurationFormatter converts time.Duration values into human-readable
+// strings like "1d 2h 7m 34s" or "1 day 2 hours 7 minutes 34 seconds".
+// Units with zero values are omitted. ParseDuration reads the output
+// back. The zero value is ready to use.
+type DurationFormatter struct {
+	// Style of the output, ShortStyle by default.
+	Style DurationStyle
+
+	// Precision is the resolution of the output, for example
+	// time.Millisecond. The smallest shown unit is the largest unit that
+	// is not bigger than the Precision. If Precision is zero, it depends
+	// on the duration: time.Second for zero and durations of at least a
+	// second, time.Millisecond for durations of at least a millisecond,
+	// and time.Microsecond or time.Nanosecond for shorter ones.
+	Precision time.Duration
+
+	// Truncate drops the remainder that is smaller than the Precision.
+	// By default the duration is rounded to the Precision.
+	Truncate bool
+
+	// MaxUnits limits the number of shown units. The duration is rounded
+	// (or truncated) to the smallest shown unit. Zero means no limit.
+	MaxUnits int
+}
+
+// DurationString converts a duration into a string with the default
+// settings of DurationFormatter, for example "2h 7m 34s" or "350ms".
+func DurationString(d time.Duration) string {
+	return DurationFormatter{}.Format(d)
+}
+
+// Format converts a duration into a human-readable string.
+func (df DurationFormatter) Format(d time.Duration) string {
+	var sign string
+	if d < 0 {
+		sign = "-"
+		if d == math.MinInt64 {
+			d++
+		}
+		d = -d
+	}
+
+	prec := df.Precision
+	if prec <= 0 {
+		prec = autoPrecision(d)
+	}
+	unit := unitIndex(prec)
+	val := df.round(d, prec)
+
+	if df.MaxUnits > 0 {
+		first := firstUnit(val, unit)
+		if last := first + df.MaxUnits - 1; last < unit {
+			unit = last
+			val = df.round(d, durationUnits[unit].size)
+		}
+	}
+
+	var parts []string
+	for i, u := range durationUnits[:unit+1] {
+		n := val / u.size
+		val -= n * u.size
+		if n == 0 && (i < unit || len(parts) > 0) {
+			continue
+		}
+		parts = append(parts, df.unitString(int64(n), u))
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// round rounds or truncates a positive duration to the precision.
+func (df DurationFormatter) round(d, prec time.Duration) time.Duration {
+	if df.Truncate {
+		return d.Truncate(prec)
+	}
+	res := d.Round(prec)
+	if res < 0 {
+		// Round saturates at the maximum duration
+		return d.Truncate(prec)
+	}
+	return res
+}
+
+func (df DurationFormatter) unitString(n int64, u durationUnit) string {
+	num := strconv.FormatInt(n, 10)
+	if df.Style != LongStyle {
+		return num + u.short
+	}
+	if n == 1 {
+		return num + " " + u.long
+	}
+	return num + " " + u.plural
+}
+
+// autoPrecision chooses the precision for a duration when it is not set.
+func autoPrecision(d time.Duration) time.Duration {
+	switch {
+	case d == 0 || d >= time.Second:
+		return time.Second
+	case d >= time.Millisecond:
+		return time.Millisecond
+	case d >= time.Microsecond:
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
+
+// unitIndex returns the index of the largest unit that is not bigger
+// than the precision.
+func unitIndex(prec time.Duration) int {
+	for i, u := range durationUnits {
+		if u.size <= prec {
+			return i
+		}
+	}
+	return len(durationUnits) - 1
+}
+
+// firstUnit returns the index of the largest unit with a non-zero value,
+// or the index of the smallest unit if the duration is shorter.
+func firstUnit(d time.Duration, smallest int) int {
+	for i, u := range durationUnits[:smallest] {
+		if d >= u.size {
+			return i
+		}
+	}
+	return smallest
+}
+
+// ParseDuration converts strings created by DurationFormatter and
+// TimeString back into durations. It accepts both short ("2h 7m 34s")
+// and long ("2 hours 7 minutes") styles, fractional values ("1.5h"),
+// and a leading minus sign.
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = strings.TrimSpace(s[1:])
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("cannot parse empty duration '%s'", orig)
+	}
+
+	parseErr := func(err error) error {
+		return fmt.Errorf("cannot parse duration '%s': %w", orig, err)
+	}
+
+	var res time.Duration
+	for i := 0; i < len(fields); i++ {
+		num, name := splitNumber(fields[i])
+		if num == "" {
+			return 0, parseErr(fmt.Errorf("number expected in '%s'", fields[i]))
+		}
+		if name == "" {
+			if i+1 == len(fields) {
+				return 0, parseErr(fmt.Errorf("no unit after '%s'", num))
+			}
+			i++
+			name = fields[i]
+		}
+
+		u, ok := findUnit(name)
+		if !ok {
+			return 0, parseErr(fmt.Errorf("unknown unit '%s'", name))
+		}
+		d, err := unitValue(num, u.size)
+		if err != nil {
+			return 0, parseErr(err)
+		}
+		if res > math.MaxInt64-d {
+			return 0, parseErr(errDurationRange)
+		}
+		res += d
+	}
+
+	if neg {
+		res = -res
+	}
+	return res, nil
+}
+
+var errDurationRange = errors.New("duration is out of range")
+
+// splitNumber splits a string into a leading number and the rest.
+func splitNumber(s string) (string, string) {
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// findUnit finds a unit by any of its names.
+func findUnit(name string) (durationUnit, bool) {
+	lower := strings.ToLower(name)
+	for _, u := range durationUnits {
+		if name == u.short || lower == u.long || lower == u.plural {
+			return u, true
+		}
+		for _, v := range u.aliases {
+			if lower == v {
+				return u, true
+			}
+		}
+	}
+	return durationUnit{}, false
+}
+
+// unitValue converts a number of units into a duration.
+func unitValue(num string, size time.Duration) (time.Duration, error) {
+	intPart, fracPart, _ := strings.Cut(num, ".")
+	var res time.Duration
+	if intPart != "" {
+		n, err := strconv.ParseInt(intPart, 10, 64)
+		if err != nil || n > int64(math.MaxInt64/size) {
+			return 0, errDurationRange
+		}
+		res = time.Duration(n) * size
+	}
+	if fracPart != "" {
+		f, err := strconv.ParseFloat("0."+fracPart, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number '%s'", num)
+		}
+		frac := time.Duration(math.Round(f * float64(size)))
+		if res > math.MaxInt64-frac {
+			return 0, errDurationRange
+		}
+		res += frac
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("invalid number '%s'", num)
+	}
+	return res, nil
+}
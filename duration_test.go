@@ -0,0 +1,134 @@
+package gnfmt_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationString(t *testing.T) {
+	tests := []struct {
+		name string
+		dur  time.Duration
+		str  string
+	}{
+		{"zero", 0, "0s"},
+		{"sec", 30300 * time.Millisecond, "30s"},
+		{"round", 30600 * time.Millisecond, "31s"},
+		{"ms", 400 * time.Millisecond, "400ms"},
+		{"ms round", 12345 * time.Microsecond, "12ms"},
+		{"µs", 1500 * time.Nanosecond, "2µs"},
+		{"ns", 15, "15ns"},
+		{"hr", 7654 * time.Second, "2h 7m 34s"},
+		{"d", 88_000 * time.Second, "1d 26m 40s"},
+		{"zero units", 90_000_000 * time.Second, "1041d 16h"},
+		{"negative", -185 * time.Second, "-3m 5s"},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			assert.Equal(t, v.str, gnfmt.DurationString(v.dur))
+		})
+	}
+}
+
+func TestDurationFormatter(t *testing.T) {
+	d := 2*time.Hour + 7*time.Minute + 34*time.Second + 567*time.Millisecond
+	tests := []struct {
+		name string
+		df   gnfmt.DurationFormatter
+		dur  time.Duration
+		str  string
+	}{
+		{"default", gnfmt.DurationFormatter{}, d, "2h 7m 35s"},
+		{"truncate", gnfmt.DurationFormatter{Truncate: true}, d, "2h 7m 34s"},
+		{"ms", gnfmt.DurationFormatter{Precision: time.Millisecond}, d,
+			"2h 7m 34s 567ms"},
+		{"100ms", gnfmt.DurationFormatter{Precision: 100 * time.Millisecond},
+			d, "2h 7m 34s 600ms"},
+		{"minute", gnfmt.DurationFormatter{Precision: time.Minute}, d, "2h 8m"},
+		{"max units", gnfmt.DurationFormatter{MaxUnits: 2}, d, "2h 8m"},
+		{"max units trunc",
+			gnfmt.DurationFormatter{MaxUnits: 2, Truncate: true}, d, "2h 7m"},
+		{"max units carry", gnfmt.DurationFormatter{MaxUnits: 1},
+			59*time.Minute + 31*time.Second, "1h"},
+		{"max units short", gnfmt.DurationFormatter{MaxUnits: 3},
+			3 * time.Second, "3s"},
+		{"long", gnfmt.DurationFormatter{Style: gnfmt.LongStyle}, d,
+			"2 hours 7 minutes 35 seconds"},
+		{"long single", gnfmt.DurationFormatter{Style: gnfmt.LongStyle},
+			gnfmt.Day + time.Minute + time.Second, "1 day 1 minute 1 second"},
+		{"long zero", gnfmt.DurationFormatter{Style: gnfmt.LongStyle}, 0,
+			"0 seconds"},
+		{"long ms", gnfmt.DurationFormatter{Style: gnfmt.LongStyle},
+			time.Millisecond, "1 millisecond"},
+		{"max", gnfmt.DurationFormatter{}, math.MaxInt64,
+			"106751d 23h 47m 16s"},
+		{"min", gnfmt.DurationFormatter{}, math.MinInt64,
+			"-106751d 23h 47m 16s"},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			assert.Equal(t, v.str, v.df.Format(v.dur))
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		str string
+		dur time.Duration
+	}{
+		{"0s", 0},
+		{"2h 7m 34s", 7654 * time.Second},
+		{"2 hours 7 minutes 34 seconds", 7654 * time.Second},
+		{"1 day 1 Minute", gnfmt.Day + time.Minute},
+		{"1041d 16h 2m 40sec", 90_000_160 * time.Second},
+		{"1.5h", 90 * time.Minute},
+		{"  -3m 5s ", -185 * time.Second},
+		{"12ms 5µs 3ns", 12*time.Millisecond + 5*time.Microsecond + 3},
+		{"5us 5 μs", 10 * time.Microsecond},
+		{".5s", 500 * time.Millisecond},
+		{"2562047.5h", 2562047*time.Hour + 30*time.Minute},
+	}
+
+	for _, v := range tests {
+		res, err := gnfmt.ParseDuration(v.str)
+		assert.Nil(err, v.str)
+		assert.Equal(v.dur, res, v.str)
+	}
+
+	for _, v := range []string{
+		"", "-", "5", "5 parsecs", "h", "1..5s", "5s 3", "200000d",
+		"106751d 24h", "106751.99999d", "2562047.9h",
+	} {
+		_, err := gnfmt.ParseDuration(v)
+		assert.NotNil(err, v)
+	}
+	_, err := gnfmt.ParseDuration("106751.99999d")
+	assert.ErrorContains(err, "out of range")
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	durs := []time.Duration{
+		0, 1, 999, 1234567, 7654 * time.Second, -88_000 * time.Second,
+		math.MaxInt64, math.MinInt64 + 1,
+	}
+	for _, d := range durs {
+		for _, style := range []gnfmt.DurationStyle{gnfmt.ShortStyle, gnfmt.LongStyle} {
+			df := gnfmt.DurationFormatter{Style: style, Precision: time.Nanosecond}
+			res, err := gnfmt.ParseDuration(df.Format(d))
+			assert.Nil(err)
+			assert.Equal(d, res)
+		}
+	}
+	res, err := gnfmt.ParseDuration(gnfmt.TimeString(7654))
+	assert.Nil(err)
+	assert.Equal(7654*time.Second, res)
+}
@@ -19,6 +19,12 @@ func TestEncodeDecode(t *testing.T) {
 	encs := []Encoder{
 		GNgob{},
 		GNjson{},
+		GNmsgpack{},
+		GNcbor{},
+		GNcbor{Canonical: true, TimeTag: true},
+		GNyaml{},
+		GNxml{},
+		GNxml{Root: "ver", Pretty: true},
 	}
 	for _, e := range encs {
 		obj := version{
@@ -65,6 +71,16 @@ func TestOutput(t *testing.T) {
 			},
 			output: `{"Version":"v10.10.10","Build":"today"}`,
 		},
+		{
+			name:   "jsonl",
+			format: JSONL,
+			input: []version{
+				{Version: "v10.10.10", Build: "today"},
+				{Version: "v10.10.11", Build: "tomorrow"},
+			},
+			output: `{"Version":"v10.10.10","Build":"today"}
+{"Version":"v10.10.11","Build":"tomorrow"}`,
+		},
 		{
 			name:   "csv",
 			format: CSV,
@@ -0,0 +1,232 @@
+package gnfmt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// EncoderID identifies an Encoder that created the payload of an envelope.
+type EncoderID uint8
+
+const (
+	// UnknownEncoder is for encoders that cannot be used in envelopes.
+	UnknownEncoder EncoderID = iota
+
+	// EncoderJSON identifies GNjson.
+	EncoderJSON
+
+	// EncoderGob identifies GNgob.
+	EncoderGob
+
+	// EncoderMsgpack identifies GNmsgpack.
+	EncoderMsgpack
+
+	// EncoderCBOR identifies GNcbor.
+	EncoderCBOR
+
+	// EncoderYAML identifies GNyaml.
+	EncoderYAML
+
+	// EncoderXML identifies GNxml.
+	EncoderXML
+)
+
+var encoderIDMap = map[EncoderID]string{
+	UnknownEncoder: "unknown",
+	EncoderJSON:    "JSON",
+	EncoderGob:     "gob",
+	EncoderMsgpack: "MessagePack",
+	EncoderCBOR:    "CBOR",
+	EncoderYAML:    "YAML",
+	EncoderXML:     "XML",
+}
+
+// String representation of an encoder ID.
+func (id EncoderID) String() string {
+	if res, ok := encoderIDMap[id]; ok {
+		return res
+	}
+	return encoderIDMap[UnknownEncoder]
+}
+
+// envelopeMagic starts every envelope. The first byte has the high bit
+// set, so text data is never mistaken for an envelope.
+var envelopeMagic = []byte{0x89, 'G', 'N', 'F'}
+
+const (
+	// envelopeVersion is the version of the envelope layout.
+	envelopeVersion = 1
+
+	// envelopeSize is the size of the envelope header: magic bytes,
+	// layout version, encoder ID, compression and schema version.
+	envelopeSize = 9
+)
+
+var (
+	// ErrNoEnvelope means that data does not start with an envelope.
+	ErrNoEnvelope = errors.New("data is not enveloped")
+
+	// ErrUnknownEncoder means that an encoder cannot be identified.
+	ErrUnknownEncoder = errors.New("unknown encoder")
+)
+
+// EnvelopeHeader describes the payload of an envelope.
+type EnvelopeHeader struct {
+	// Encoder identifies the Encoder that created the payload.
+	Encoder EncoderID
+
+	// Compression is the algorithm used to compress the payload.
+	Compression Compression
+
+	// Schema is a version of the data structure, set by the application.
+	// It allows to detect blobs created by older releases.
+	Schema uint16
+}
+
+// Enveloped is an Encoder that wraps the results of encoding into a
+// self-describing envelope. The envelope starts with magic bytes followed
+// by an ID of the encoder, compression algorithm and a schema version.
+// Enveloped data can be decoded by the Decode function without knowing
+// in advance which Encoder created it.
+//
+// Only encoders of this package can be used: GNjson, GNgob, GNmsgpack,
+// GNcbor, GNyaml and GNxml.
+type Enveloped struct {
+	// Encoder converts objects to bytes and back.
+	Encoder Encoder
+
+	// Compression is the algorithm used to compress encoded data.
+	Compression Compression
+
+	// Level of compression, see Compressed.
+	Level int
+
+	// Schema is a version of the data structure set by the application.
+	Schema uint16
+}
+
+// Encode encodes an object with the wrapped Encoder, compresses the
+// result if needed and puts it into an envelope.
+func (e Enveloped) Encode(input any) ([]byte, error) {
+	id := encoderID(e.Encoder)
+	if id == UnknownEncoder {
+		return nil, fmt.Errorf("%w: %T", ErrUnknownEncoder, e.Encoder)
+	}
+
+	payload, err := e.Encoder.Encode(input)
+	if err != nil {
+		return nil, err
+	}
+	if e.Compression != NoCompression {
+		payload, err = compress(payload, e.Compression, e.Level)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]byte, envelopeSize, envelopeSize+len(payload))
+	copy(res, envelopeMagic)
+	res[4] = envelopeVersion
+	res[5] = byte(id)
+	res[6] = byte(e.Compression)
+	binary.BigEndian.PutUint16(res[7:], e.Schema)
+	return append(res, payload...), nil
+}
+
+// Decode decodes enveloped data into output. The settings of Enveloped
+// are ignored, the data is decoded according to its envelope.
+func (e Enveloped) Decode(input []byte, output any) error {
+	return Decode(input, output)
+}
+
+// Decode takes data created by Enveloped, detects the encoder and the
+// compression from the envelope and decodes the data into output. If the
+// data is not enveloped, it returns ErrNoEnvelope.
+func Decode(input []byte, output any) error {
+	hdr, payload, err := ReadEnvelope(input)
+	if err != nil {
+		return err
+	}
+
+	enc := encoderByID(hdr.Encoder)
+	if enc == nil {
+		return fmt.Errorf("%w: id %d", ErrUnknownEncoder, hdr.Encoder)
+	}
+
+	if hdr.Compression != NoCompression {
+		payload, err = decompress(payload, hdr.Compression)
+		if err != nil {
+			return err
+		}
+	}
+	return enc.Decode(payload, output)
+}
+
+// IsEnveloped returns true if data starts with an envelope.
+func IsEnveloped(input []byte) bool {
+	return len(input) >= envelopeSize && bytes.HasPrefix(input, envelopeMagic)
+}
+
+// ReadEnvelope reads the envelope header of data created by Enveloped.
+// It returns the header and the payload. The payload is returned as is,
+// without decompression.
+func ReadEnvelope(input []byte) (EnvelopeHeader, []byte, error) {
+	var res EnvelopeHeader
+	if !IsEnveloped(input) {
+		return res, nil, ErrNoEnvelope
+	}
+	if v := input[4]; v != envelopeVersion {
+		return res, nil, fmt.Errorf("unsupported envelope version %d", v)
+	}
+
+	res.Encoder = EncoderID(input[5])
+	res.Compression = Compression(input[6])
+	res.Schema = binary.BigEndian.Uint16(input[7:])
+	if _, ok := compressionMap[res.Compression]; !ok {
+		return res, nil, fmt.Errorf("unknown compression %d", res.Compression)
+	}
+	return res, input[envelopeSize:], nil
+}
+
+// encoderID returns the ID of an encoder, or UnknownEncoder.
+func encoderID(enc Encoder) EncoderID {
+	switch enc.(type) {
+	case GNjson, *GNjson:
+		return EncoderJSON
+	case GNgob, *GNgob:
+		return EncoderGob
+	case GNmsgpack, *GNmsgpack:
+		return EncoderMsgpack
+	case GNcbor, *GNcbor:
+		return EncoderCBOR
+	case GNyaml, *GNyaml:
+		return EncoderYAML
+	case GNxml, *GNxml:
+		return EncoderXML
+	default:
+		return UnknownEncoder
+	}
+}
+
+// encoderByID returns an Encoder that can decode data of the given ID,
+// or nil if ID is unknown.
+func encoderByID(id EncoderID) Encoder {
+	switch id {
+	case EncoderJSON:
+		return GNjson{}
+	case EncoderGob:
+		return GNgob{}
+	case EncoderMsgpack:
+		return GNmsgpack{}
+	case EncoderCBOR:
+		return GNcbor{}
+	case EncoderYAML:
+		return GNyaml{}
+	case EncoderXML:
+		return GNxml{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package gnfmt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestEnvelope(t *testing.T) {
+	assert := assert.New(t)
+	obj := version{Version: "v10.10.10", Build: "today"}
+	tests := []struct {
+		msg   string
+		enc   Enveloped
+		encID EncoderID
+	}{
+		{"json", Enveloped{Encoder: GNjson{}, Schema: 1}, EncoderJSON},
+		{"gob", Enveloped{Encoder: GNgob{}, Compression: Zstd}, EncoderGob},
+		{
+			"msgpack",
+			Enveloped{Encoder: GNmsgpack{}, Compression: Gzip, Level: 9},
+			EncoderMsgpack,
+		},
+		{"cbor", Enveloped{Encoder: GNcbor{Canonical: true}, Schema: 300}, EncoderCBOR},
+		{"yaml", Enveloped{Encoder: &GNyaml{}, Compression: Xz}, EncoderYAML},
+		{"xml", Enveloped{Encoder: GNxml{Root: "ver"}}, EncoderXML},
+	}
+
+	for _, v := range tests {
+		res, err := v.enc.Encode(obj)
+		assert.Nil(err, v.msg)
+		assert.True(IsEnveloped(res), v.msg)
+
+		hdr, _, err := ReadEnvelope(res)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.encID, hdr.Encoder, v.msg)
+		assert.Equal(v.enc.Compression, hdr.Compression, v.msg)
+		assert.Equal(v.enc.Schema, hdr.Schema, v.msg)
+
+		var ver version
+		err = Decode(res, &ver)
+		assert.Nil(err, v.msg)
+		assert.Equal(obj, ver, v.msg)
+
+		var ver2 version
+		err = Enveloped{}.Decode(res, &ver2)
+		assert.Nil(err, v.msg)
+		assert.Equal(obj, ver2, v.msg)
+	}
+}
+
+func TestEnvelopeErr(t *testing.T) {
+	assert := assert.New(t)
+	obj := version{Version: "v10.10.10", Build: "today"}
+
+	_, err := Enveloped{Encoder: Compressed{Encoder: GNjson{}}}.Encode(obj)
+	assert.ErrorIs(err, ErrUnknownEncoder)
+
+	res, err := GNjson{}.Encode(obj)
+	assert.Nil(err)
+	assert.False(IsEnveloped(res))
+	var ver version
+	err = Decode(res, &ver)
+	assert.ErrorIs(err, ErrNoEnvelope)
+
+	res, err = Enveloped{Encoder: GNjson{}}.Encode(obj)
+	assert.Nil(err)
+	res[5] = 200
+	err = Decode(res, &ver)
+	assert.ErrorIs(err, ErrUnknownEncoder)
+}
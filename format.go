@@ -1,6 +1,11 @@
 package gnfmt
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
 
 // Format sets available output formats
 type Format int
@@ -21,36 +26,288 @@ const (
 
 	// TSV sets output to tab-separated values.
 	TSV
+
+	// JSONL sets output to JSON Lines (NDJSON), one compact JSON document
+	// per line.
+	JSONL
+
+	// YAML sets output to YAML.
+	YAML
+
+	// Markdown sets output to a GitHub-flavored Markdown table.
+	Markdown
+
+	// Table sets output to a plain-text table with aligned columns.
+	Table
+
+	// HTML sets output to an HTML table.
+	HTML
+
+	// XML sets output to XML.
+	XML
 )
 
-var formatStringMap = map[string]Format{
-	"csv": CSV, "compact": CompactJSON, "pretty": PrettyJSON, "tsv": TSV,
+// firstCustomFormat is the value given to the first format registered
+// by RegisterFormat. Values below it are reserved for formats of this
+// package.
+const firstCustomFormat Format = 256
+
+// FormatSpec describes a format for the format registry.
+type FormatSpec struct {
+	// Label is a human-readable name of the format, returned by the String
+	// method of Format.
+	Label string
+
+	// Names are strings accepted by NewFormat for the format. The first
+	// name is the main one, the rest are aliases. Names are case-insensitive.
+	Names []string
+
+	// MIMEType of the format, for example "text/csv".
+	MIMEType string
+
+	// Extensions are file extensions of the format, including the leading
+	// dot, for example ".csv".
+	Extensions []string
+
+	// NewOutputter creates an Outputter that is able to render data in
+	// the format.
+	NewOutputter func() Outputter
+}
+
+// formatRegistry keeps specifications of all known formats.
+type formatRegistry struct {
+	mu    sync.RWMutex
+	specs map[Format]FormatSpec
+	names map[string]Format
+	order []Format
+	next  Format
+}
+
+var formats = newFormatRegistry()
+
+func newFormatRegistry() *formatRegistry {
+	res := &formatRegistry{
+		specs: make(map[Format]FormatSpec),
+		names: make(map[string]Format),
+		next:  firstCustomFormat,
+	}
+
+	jsonOut := func() Outputter { return GNjson{} }
+	csvOut := func() Outputter { return GNcsv{} }
+	builtin := []struct {
+		f    Format
+		spec FormatSpec
+	}{
+		{CSV, FormatSpec{
+			Label:        "CSV",
+			Names:        []string{"csv"},
+			MIMEType:     "text/csv",
+			Extensions:   []string{".csv"},
+			NewOutputter: csvOut,
+		}},
+		{TSV, FormatSpec{
+			Label:        "TSV",
+			Names:        []string{"tsv"},
+			MIMEType:     "text/tab-separated-values",
+			Extensions:   []string{".tsv", ".tab"},
+			NewOutputter: csvOut,
+		}},
+		{CompactJSON, FormatSpec{
+			Label:        "compact JSON",
+			Names:        []string{"compact", "json"},
+			MIMEType:     "application/json",
+			Extensions:   []string{".json"},
+			NewOutputter: jsonOut,
+		}},
+		{PrettyJSON, FormatSpec{
+			Label:        "pretty JSON",
+			Names:        []string{"pretty"},
+			MIMEType:     "application/json",
+			Extensions:   []string{".json"},
+			NewOutputter: jsonOut,
+		}},
+		{JSONL, FormatSpec{
+			Label:        "JSON Lines",
+			Names:        []string{"jsonl", "ndjson"},
+			MIMEType:     "application/x-ndjson",
+			Extensions:   []string{".jsonl", ".ndjson"},
+			NewOutputter: jsonOut,
+		}},
+		{YAML, FormatSpec{
+			Label:        "YAML",
+			Names:        []string{"yaml", "yml"},
+			MIMEType:     "application/yaml",
+			Extensions:   []string{".yaml", ".yml"},
+			NewOutputter: func() Outputter { return GNyaml{} },
+		}},
+		{Markdown, FormatSpec{
+			Label:        "Markdown",
+			Names:        []string{"markdown", "md"},
+			MIMEType:     "text/markdown",
+			Extensions:   []string{".md", ".markdown"},
+			NewOutputter: func() Outputter { return GNmarkdown{} },
+		}},
+		{Table, FormatSpec{
+			Label:        "text table",
+			Names:        []string{"table"},
+			MIMEType:     "text/plain",
+			NewOutputter: func() Outputter { return GNtable{} },
+		}},
+		{HTML, FormatSpec{
+			Label:        "HTML",
+			Names:        []string{"html"},
+			MIMEType:     "text/html",
+			Extensions:   []string{".html", ".htm"},
+			NewOutputter: func() Outputter { return GNhtml{} },
+		}},
+		{XML, FormatSpec{
+			Label:        "XML",
+			Names:        []string{"xml"},
+			MIMEType:     "application/xml",
+			Extensions:   []string{".xml"},
+			NewOutputter: func() Outputter { return GNxml{Pretty: true} },
+		}},
+	}
+
+	for _, v := range builtin {
+		if err := res.add(v.f, v.spec); err != nil {
+			panic(err)
+		}
+	}
+	return res
+}
+
+// add registers a specification for a format. It must be called
+// with the lock held, or during initialization.
+func (r *formatRegistry) add(f Format, spec FormatSpec) error {
+	if len(spec.Names) == 0 {
+		return errors.New("format must have at least one name")
+	}
+	names := make([]string, len(spec.Names))
+	for i, v := range spec.Names {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			return errors.New("format name cannot be empty")
+		}
+		if _, ok := r.names[v]; ok {
+			return fmt.Errorf("format name '%s' is already registered", v)
+		}
+		names[i] = v
+	}
+
+	spec.Names = names
+	for _, v := range names {
+		r.names[v] = f
+	}
+	r.specs[f] = spec
+	r.order = append(r.order, f)
+	return nil
+}
+
+// RegisterFormat adds a new format to the format registry. After
+// registration NewFormat recognizes names of the format, and the String
+// method returns its label. It returns the new Format, or an error if the
+// specification has no names, or if one of the names is already taken.
+//
+// Values of registered formats depend on the order of registration, so
+// they should not be persisted.
+func RegisterFormat(spec FormatSpec) (Format, error) {
+	formats.mu.Lock()
+	defer formats.mu.Unlock()
+
+	f := formats.next
+	if err := formats.add(f, spec); err != nil {
+		return FormatNone, err
+	}
+	formats.next++
+	return f, nil
+}
+
+// Formats returns all registered formats in the order of their
+// registration.
+func Formats() []Format {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+	res := make([]Format, len(formats.order))
+	copy(res, formats.order)
+	return res
 }
 
-var formatMap = map[Format]string{
-	FormatNone:  "",
-	CSV:         "CSV",
-	CompactJSON: "compact JSON",
-	PrettyJSON:  "pretty JSON",
-	TSV:         "TSV",
+// Spec returns the specification of a format from the format registry.
+// If the format is not registered, it returns false.
+func (f Format) Spec() (FormatSpec, bool) {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+	res, ok := formats.specs[f]
+	return res, ok
 }
 
 // String representation of a format.
 func (f Format) String() string {
-	return formatMap[f]
+	spec, _ := f.Spec()
+	return spec.Label
+}
+
+// MIMEType returns the MIME type of a format, or an empty string if it is
+// unknown.
+func (f Format) MIMEType() string {
+	spec, _ := f.Spec()
+	return spec.MIMEType
+}
+
+// Outputter creates an Outputter for the format. It returns nil if the
+// format does not provide one.
+func (f Format) Outputter() Outputter {
+	spec, ok := f.Spec()
+	if !ok || spec.NewOutputter == nil {
+		return nil
+	}
+	return spec.NewOutputter()
+}
+
+// formatByExt returns the first registered format with the given file
+// extension.
+func formatByExt(ext string) (Format, bool) {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+	for _, f := range formats.order {
+		for _, v := range formats.specs[f].Extensions {
+			if strings.EqualFold(v, ext) {
+				return f, true
+			}
+		}
+	}
+	return FormatNone, false
 }
 
 // NewFormat is a constructor that converts a string into a corresponding format.
 // If string cannot be converted, the constructor returns an error and
 // and FormatNone format.
 func NewFormat(s string) (Format, error) {
-	if f, ok := formatStringMap[s]; ok {
+	formats.mu.RLock()
+	defer formats.mu.RUnlock()
+
+	if f, ok := formats.names[strings.ToLower(s)]; ok {
 		return f, nil
 	}
 
+	names := make([]string, len(formats.order))
+	for i, f := range formats.order {
+		names[i] = "'" + formats.specs[f].Names[0] + "'"
+	}
+	var list string
+	switch len(names) {
+	case 0:
+	case 1:
+		list = names[0]
+	default:
+		last := len(names) - 1
+		list = strings.Join(names[:last], ", ") + " or " + names[last]
+	}
+
 	err := fmt.Errorf(
-		"cannot convert '%s' to format, use 'csv', 'tsv', 'compact' or 'pretty' as input",
-		s,
+		"cannot convert '%s' to format, use %s as input",
+		s, list,
 	)
 	return FormatNone, err
 }
@@ -1,10 +1,13 @@
 package gnfmt_test
 
 import (
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gnames/gnfmt"
 	"github.com/matryer/is"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewFormat(t *testing.T) {
@@ -19,6 +22,12 @@ func TestNewFormat(t *testing.T) {
 		{"compact", "compact", gnfmt.CompactJSON, true},
 		{"pretty", "pretty", gnfmt.PrettyJSON, true},
 		{"tsv", "tsv", gnfmt.TSV, true},
+		{"jsonl", "jsonl", gnfmt.JSONL, true},
+		{"yaml", "yaml", gnfmt.YAML, true},
+		{"md", "md", gnfmt.Markdown, true},
+		{"table", "table", gnfmt.Table, true},
+		{"html", "html", gnfmt.HTML, true},
+		{"xml", "xml", gnfmt.XML, true},
 		{"bad", "bad", gnfmt.FormatNone, false},
 	}
 	for _, v := range tests {
@@ -41,6 +50,12 @@ func TestString(t *testing.T) {
 		{"tsv", gnfmt.TSV, "TSV"},
 		{"compact", gnfmt.CompactJSON, "compact JSON"},
 		{"pretty", gnfmt.PrettyJSON, "pretty JSON"},
+		{"jsonl", gnfmt.JSONL, "JSON Lines"},
+		{"yaml", gnfmt.YAML, "YAML"},
+		{"markdown", gnfmt.Markdown, "Markdown"},
+		{"table", gnfmt.Table, "text table"},
+		{"html", gnfmt.HTML, "HTML"},
+		{"xml", gnfmt.XML, "XML"},
 	}
 	for _, v := range tests {
 		t.Run(v.name, func(_ *testing.T) {
@@ -49,3 +64,66 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+type upperOutputter struct{}
+
+func (upperOutputter) Output(record any, f gnfmt.Format) string {
+	s, ok := record.(string)
+	if !ok {
+		return ""
+	}
+	return strings.ToUpper(s)
+}
+
+var registerUpper = sync.OnceValues(func() (gnfmt.Format, error) {
+	return gnfmt.RegisterFormat(gnfmt.FormatSpec{
+		Label:        "upper case text",
+		Names:        []string{"upper", "UC"},
+		MIMEType:     "text/plain",
+		Extensions:   []string{".up"},
+		NewOutputter: func() gnfmt.Outputter { return upperOutputter{} },
+	})
+})
+
+func TestRegisterFormat(t *testing.T) {
+	assert := assert.New(t)
+	f, err := registerUpper()
+	assert.Nil(err)
+	assert.Equal("upper case text", f.String())
+	assert.Equal("text/plain", f.MIMEType())
+	assert.Contains(gnfmt.Formats(), f)
+
+	for _, v := range []string{"upper", "uc", "Upper"} {
+		f2, err := gnfmt.NewFormat(v)
+		assert.Nil(err)
+		assert.Equal(f, f2)
+	}
+	assert.Equal("ABC", f.Outputter().Output("abc", f))
+
+	_, err = gnfmt.NewFormat("bad")
+	assert.ErrorContains(err, "'upper'")
+
+	_, err = gnfmt.RegisterFormat(gnfmt.FormatSpec{Names: []string{"csv"}})
+	assert.NotNil(err)
+	_, err = gnfmt.RegisterFormat(gnfmt.FormatSpec{Label: "no names"})
+	assert.NotNil(err)
+}
+
+func TestFormatSpec(t *testing.T) {
+	assert := assert.New(t)
+	f, err := gnfmt.NewFormat("json")
+	assert.Nil(err)
+	assert.Equal(gnfmt.CompactJSON, f)
+	assert.Equal("application/json", f.MIMEType())
+
+	spec, ok := gnfmt.TSV.Spec()
+	assert.True(ok)
+	assert.Equal([]string{".tsv", ".tab"}, spec.Extensions)
+	assert.IsType(gnfmt.GNcsv{}, gnfmt.TSV.Outputter())
+	assert.IsType(gnfmt.GNyaml{}, gnfmt.YAML.Outputter())
+
+	_, ok = gnfmt.FormatNone.Spec()
+	assert.False(ok)
+	assert.Nil(gnfmt.FormatNone.Outputter())
+	assert.Equal("", gnfmt.FormatNone.String())
+}
@@ -0,0 +1,133 @@
+package gnfmt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// GNcbor allows to encode and decode CBOR format (RFC 8949). Names of
+// struct fields are taken from `cbor` tags. If a field does not have such
+// a tag, its `json` tag is used instead. Maps decoded into `any` values
+// are created as map[string]any.
+//
+// CBOR is a binary format, so GNcbor does not implement the Outputter
+// interface.
+type GNcbor struct {
+	// Canonical enables Core Deterministic Encoding (RFC 8949,
+	// section 4.2): map keys are sorted, numbers use their shortest form,
+	// and indefinite-length items are forbidden. The same object is always
+	// encoded into the same bytes.
+	Canonical bool
+
+	// TimeTag adds the standard date/time tag (tag 0) to time.Time
+	// values. Without it time values are encoded as plain RFC 3339
+	// strings.
+	TimeTag bool
+}
+
+var (
+	cborEncModes sync.Map
+	cborDecMode  = sync.OnceValues(func() (cbor.DecMode, error) {
+		return cbor.DecOptions{
+			DefaultMapType: reflect.TypeFor[map[string]any](),
+		}.DecMode()
+	})
+)
+
+// Encode takes an object and converts it into CBOR bytes. It returns an
+// error if the encoding fails.
+func (e GNcbor) Encode(input any) ([]byte, error) {
+	em, err := e.encMode()
+	if err != nil {
+		return nil, err
+	}
+	return em.Marshal(input)
+}
+
+// Decode converts CBOR bytes into a Go object. If decoding breaks, it
+// returns an error.
+func (e GNcbor) Decode(input []byte, output any) error {
+	dm, err := cborDecMode()
+	if err != nil {
+		return err
+	}
+	return dm.NewDecoder(bytes.NewReader(input)).Decode(output)
+}
+
+// EncodeTo converts an object into CBOR and writes it to w.
+func (e GNcbor) EncodeTo(w io.Writer, input any) error {
+	em, err := e.encMode()
+	if err != nil {
+		return err
+	}
+	return em.NewEncoder(w).Encode(input)
+}
+
+// DecodeFrom reads one CBOR data item from r and decodes it into output.
+func (e GNcbor) DecodeFrom(r io.Reader, output any) error {
+	dm, err := cborDecMode()
+	if err != nil {
+		return err
+	}
+	return dm.NewDecoder(r).Decode(output)
+}
+
+// EncodeStream writes objects from the inputs channel to w as a sequence
+// of CBOR data items (RFC 8742).
+func (e GNcbor) EncodeStream(
+	ctx context.Context,
+	w io.Writer,
+	inputs <-chan any,
+) error {
+	em, err := e.encMode()
+	if err != nil {
+		return err
+	}
+	enc := em.NewEncoder(w)
+	return encodeStream(ctx, inputs, enc.Encode)
+}
+
+// DecodeStream reads a sequence of CBOR data items from r, decodes them
+// into objects created by newOutput and sends them to the outputs channel.
+func (e GNcbor) DecodeStream(
+	ctx context.Context,
+	r io.Reader,
+	newOutput func() any,
+	outputs chan<- any,
+) (int, error) {
+	dm, err := cborDecMode()
+	if err != nil {
+		return 0, err
+	}
+	dec := dm.NewDecoder(r)
+	return decodeStream(ctx, newOutput, outputs, dec.Decode)
+}
+
+// encMode returns an encoding mode that corresponds to the settings of
+// GNcbor. Modes are created once and reused.
+func (e GNcbor) encMode() (cbor.EncMode, error) {
+	if em, ok := cborEncModes.Load(e); ok {
+		return em.(cbor.EncMode), nil
+	}
+
+	opts := cbor.EncOptions{}
+	if e.Canonical {
+		opts = cbor.CoreDetEncOptions()
+	}
+	opts.Time = cbor.TimeRFC3339Nano
+	if e.TimeTag {
+		opts.TimeTag = cbor.EncTagRequired
+	}
+
+	em, err := opts.EncMode()
+	if err != nil {
+		return nil, err
+	}
+	cborEncModes.Store(e, em)
+	return em, nil
+}
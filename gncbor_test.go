@@ -0,0 +1,68 @@
+package gnfmt_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestCborCanonical(t *testing.T) {
+	assert := assert.New(t)
+	enc := GNcbor{Canonical: true}
+	m1 := map[string]int{"b": 2, "a": 1, "ccc": 3, "dd": 4}
+	res, err := enc.Encode(m1)
+	assert.Nil(err)
+	for range 10 {
+		res2, err := enc.Encode(map[string]int{"dd": 4, "ccc": 3, "a": 1, "b": 2})
+		assert.Nil(err)
+		assert.Equal(res, res2)
+	}
+	// map(4), "a": 1, "b": 2, "dd": 4, "ccc": 3
+	assert.Equal(
+		[]byte{0xa4, 0x61, 'a', 0x01, 0x61, 'b', 0x02,
+			0x62, 'd', 'd', 0x04, 0x63, 'c', 'c', 'c', 0x03},
+		res,
+	)
+}
+
+func TestCborTimeTag(t *testing.T) {
+	assert := assert.New(t)
+	type event struct {
+		Name string    `json:"name"`
+		Date time.Time `cbor:"date"`
+	}
+	date := time.Date(2024, 5, 1, 10, 20, 30, 500, time.UTC)
+	obj := event{Name: "release", Date: date}
+
+	tests := []struct {
+		msg    string
+		enc    GNcbor
+		tagged bool
+	}{
+		{"no tag", GNcbor{}, false},
+		{"tag", GNcbor{TimeTag: true}, true},
+	}
+
+	for _, v := range tests {
+		res, err := v.enc.Encode(obj)
+		assert.Nil(err, v.msg)
+		// tag 0 is encoded as 0xc0 before the date string
+		assert.Equal(v.tagged, slices.Contains(res, 0xc0), v.msg)
+
+		var m map[string]any
+		err = v.enc.Decode(res, &m)
+		assert.Nil(err, v.msg)
+		assert.Contains(m, "name", v.msg)
+		assert.Contains(m, "date", v.msg)
+
+		var obj2 event
+		err = v.enc.Decode(res, &obj2)
+		assert.Nil(err, v.msg)
+		assert.True(date.Equal(obj2.Date), v.msg)
+		assert.Equal("release", obj2.Name, v.msg)
+	}
+}
@@ -0,0 +1,52 @@
+package gnfmt
+
+import "strings"
+
+// GNcsv converts structs or slices of structs into CSV or TSV output.
+// The first row of the output contains the names of the fields. Names are
+// taken from `csv` tags, or, if they are absent, from `json` tags, or from
+// the names of the fields. Fields tagged with "-" are ignored. Fields of
+// nested structs are flattened, their names are joined by a dot (for
+// example "Name.Canonical"). Data given as [][]string is used as is,
+// its first row is treated as a header.
+type GNcsv struct {
+	// NoHeader removes the row with field names from the output.
+	NoHeader bool
+}
+
+// Output converts a struct or a slice of structs into CSV or TSV string.
+// In case of a problem it returns an empty string, use OutputE to find
+// out the reason.
+func (e GNcsv) Output(record any, f Format) string {
+	res, _ := e.OutputE(record, f)
+	return res
+}
+
+// OutputE converts a struct or a slice of structs into CSV or TSV string
+// according to the given format. Rows are separated by a new line. For
+// all other formats it returns ErrUnsupportedFormat.
+func (e GNcsv) OutputE(record any, f Format) (string, error) {
+	var sep rune
+	switch f {
+	case CSV:
+		sep = ','
+	case TSV:
+		sep = '\t'
+	default:
+		return "", unsupportedFormat(f)
+	}
+
+	tbl, err := tabulate(record)
+	if err != nil {
+		return "", err
+	}
+
+	res := make([]string, 0, len(tbl.rows)+1)
+	if !e.NoHeader {
+		res = append(res, ToCSV(tbl.header, sep))
+	}
+	for _, row := range tbl.rows {
+		res = append(res, ToCSV(row, sep))
+	}
+	return strings.Join(res, "\n"), nil
+}
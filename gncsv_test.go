@@ -0,0 +1,134 @@
+package gnfmt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+type canonical struct {
+	Simple string `json:"simple"`
+	Full   string `csv:"canonicalFull" json:"full"`
+}
+
+type nameRecord struct {
+	ID        int        `csv:"id"`
+	Name      string     `json:"name"`
+	Canonical canonical  `json:"canonical"`
+	Authors   []string   `json:"authors"`
+	Cardinal  *int       `json:"cardinality"`
+	Date      time.Time  `json:"date"`
+	Parent    *canonical `json:"parent"`
+	Secret    string     `csv:"-"`
+	note      string
+}
+
+func TestGNcsvOutput(t *testing.T) {
+	assert := assert.New(t)
+	card := 2
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	recs := []nameRecord{
+		{
+			ID:   1,
+			Name: "Bubo bubo (Linnaeus, 1758)",
+			Canonical: canonical{
+				Simple: "Bubo bubo",
+				Full:   "Bubo bubo",
+			},
+			Authors:  []string{"Linnaeus"},
+			Cardinal: &card,
+			Date:     date,
+			Secret:   "hidden",
+			note:     "hidden",
+		},
+		{
+			ID:     2,
+			Name:   "Aus \"bus\"",
+			Parent: &canonical{Simple: "Aus", Full: "Aus"},
+		},
+	}
+
+	header := "id,name,canonical.simple,canonical.canonicalFull,authors," +
+		"cardinality,date,parent.simple,parent.canonicalFull"
+	tests := []struct {
+		msg    string
+		enc    GNcsv
+		input  any
+		format Format
+		output string
+	}{
+		{
+			msg:    "csv slice",
+			input:  recs,
+			format: CSV,
+			output: header + "\n" +
+				`1,"Bubo bubo (Linnaeus, 1758)",Bubo bubo,Bubo bubo,` +
+				`"[""Linnaeus""]",2,2024-05-01T00:00:00Z,,` + "\n" +
+				`2,"Aus ""bus""",,,null,,0001-01-01T00:00:00Z,Aus,Aus`,
+		},
+		{
+			msg:    "tsv struct",
+			input:  recs[0],
+			format: TSV,
+			output: "id\tname\tcanonical.simple\tcanonical.canonicalFull\t" +
+				"authors\tcardinality\tdate\tparent.simple\tparent.canonicalFull\n" +
+				"1\tBubo bubo (Linnaeus, 1758)\tBubo bubo\tBubo bubo\t" +
+				"\"[\"\"Linnaeus\"\"]\"\t2\t2024-05-01T00:00:00Z\t\t",
+		},
+		{
+			msg:    "no header",
+			enc:    GNcsv{NoHeader: true},
+			input:  &recs[1],
+			format: CSV,
+			output: `2,"Aus ""bus""",,,null,,0001-01-01T00:00:00Z,Aus,Aus`,
+		},
+		{
+			msg:    "empty slice",
+			input:  []canonical{},
+			format: CSV,
+			output: "simple,canonicalFull",
+		},
+		{
+			msg:    "rows",
+			input:  [][]string{{"id", "name"}, {"1", "Bubo, bubo"}},
+			format: CSV,
+			output: "id,name\n1,\"Bubo, bubo\"",
+		},
+		{
+			msg:    "json format",
+			input:  recs,
+			format: CompactJSON,
+			output: "",
+		},
+		{
+			msg:    "not struct",
+			input:  []int{1, 2, 3},
+			format: CSV,
+			output: "",
+		},
+	}
+
+	for _, v := range tests {
+		res := v.enc.Output(v.input, v.format)
+		assert.Equal(v.output, res, v.msg)
+	}
+}
+
+type node struct {
+	Name   string
+	Parent *node
+}
+
+func TestGNcsvCyclicType(t *testing.T) {
+	assert := assert.New(t)
+	var enc GNcsv
+	res := enc.Output(node{Name: "a"}, CSV)
+	assert.Equal("Name,Parent\na,", res)
+
+	n := node{Name: "b", Parent: &node{Name: "a"}}
+	res = enc.Output([]node{n}, CSV)
+	assert.Equal("Name,Parent\n"+`b,"{""Name"":""a"",""Parent"":null}"`, res)
+}
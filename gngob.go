@@ -2,7 +2,9 @@ package gnfmt
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
+	"io"
 )
 
 // GNgob is for serializing data into gob format.
@@ -26,3 +28,39 @@ func (e GNgob) Decode(input []byte, output any) error {
 	dec := gob.NewDecoder(b)
 	return dec.Decode(output)
 }
+
+// EncodeTo serializes an object into gob format and writes it to w.
+func (e GNgob) EncodeTo(w io.Writer, input any) error {
+	return gob.NewEncoder(w).Encode(input)
+}
+
+// DecodeFrom reads a gob-encoded object from r and deserializes it into
+// output. The decoder might read from r more data than it needs for one
+// object, so use DecodeStream to read several objects from one reader.
+func (e GNgob) DecodeFrom(r io.Reader, output any) error {
+	return gob.NewDecoder(r).Decode(output)
+}
+
+// EncodeStream serializes objects from the inputs channel into one gob
+// stream. Type information is sent only once per stream (see GobWriter).
+func (e GNgob) EncodeStream(
+	ctx context.Context,
+	w io.Writer,
+	inputs <-chan any,
+) error {
+	enc := NewGobWriter(w)
+	return encodeStream(ctx, inputs, enc.Encode)
+}
+
+// DecodeStream deserializes a gob stream created by EncodeStream. Every
+// object is decoded into a new output created by newOutput and sent to
+// the outputs channel.
+func (e GNgob) DecodeStream(
+	ctx context.Context,
+	r io.Reader,
+	newOutput func() any,
+	outputs chan<- any,
+) (int, error) {
+	dec := NewGobReader(r)
+	return decodeStream(ctx, newOutput, outputs, dec.Decode)
+}
@@ -0,0 +1,81 @@
+package gnfmt
+
+import (
+	"html"
+	"strings"
+)
+
+// GNhtml converts structs, slices of structs or [][]string data into
+// an HTML table with <thead> and <tbody> sections. Column names for
+// structs are created the same way as for GNcsv. For [][]string data the
+// first row is a header. The content of cells is HTML-escaped, new lines
+// are replaced by <br>.
+type GNhtml struct {
+	// TableClass is a CSS class of the <table> element.
+	TableClass string
+
+	// HeadClass is a CSS class of the <thead> element.
+	HeadClass string
+
+	// BodyClass is a CSS class of the <tbody> element.
+	BodyClass string
+
+	// RowClass is a CSS class of <tr> elements in <tbody>.
+	RowClass string
+}
+
+// Output converts data into an HTML table. For formats other than HTML,
+// or in case of a problem, it returns an empty string, use OutputE to
+// find out the reason.
+func (e GNhtml) Output(record any, f Format) string {
+	res, _ := e.OutputE(record, f)
+	return res
+}
+
+// OutputE converts data into an HTML table. For formats other than HTML
+// it returns ErrUnsupportedFormat.
+func (e GNhtml) OutputE(record any, f Format) (string, error) {
+	if f != HTML {
+		return "", unsupportedFormat(f)
+	}
+	tbl, err := tabulate(record)
+	if err != nil {
+		return "", err
+	}
+	if len(tbl.header) == 0 {
+		return "", errNoColumns
+	}
+
+	var b strings.Builder
+	b.WriteString("<table" + classAttr(e.TableClass) + ">\n")
+	b.WriteString("  <thead" + classAttr(e.HeadClass) + ">\n")
+	writeHTMLRow(&b, "th", "", tbl.header)
+	b.WriteString("  </thead>\n")
+	b.WriteString("  <tbody" + classAttr(e.BodyClass) + ">\n")
+	for _, row := range tbl.rows {
+		writeHTMLRow(&b, "td", e.RowClass, NormRowSize(row, len(tbl.header)))
+	}
+	b.WriteString("  </tbody>\n")
+	b.WriteString("</table>")
+	return b.String(), nil
+}
+
+func writeHTMLRow(b *strings.Builder, cell, class string, row []string) {
+	b.WriteString("    <tr" + classAttr(class) + ">")
+	for _, v := range row {
+		v = html.EscapeString(v)
+		v = strings.ReplaceAll(v, "\r\n", "\n")
+		v = strings.ReplaceAll(v, "\n", "<br>")
+		b.WriteString("<" + cell + ">" + v + "</" + cell + ">")
+	}
+	b.WriteString("</tr>\n")
+}
+
+// classAttr creates a class attribute for an HTML element, or an empty
+// string if the class is not set.
+func classAttr(class string) string {
+	if class == "" {
+		return ""
+	}
+	return ` class="` + html.EscapeString(class) + `"`
+}
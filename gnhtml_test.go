@@ -0,0 +1,71 @@
+package gnfmt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestHTMLOutput(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg    string
+		enc    GNhtml
+		input  any
+		format Format
+		output string
+	}{
+		{
+			msg: "rows",
+			input: [][]string{
+				{"id", "name"},
+				{"1", "<b>Bubo</b> & bubo"},
+				{"2", "line1\nline2"},
+				{"3"},
+			},
+			format: HTML,
+			output: `<table>
+  <thead>
+    <tr><th>id</th><th>name</th></tr>
+  </thead>
+  <tbody>
+    <tr><td>1</td><td>&lt;b&gt;Bubo&lt;/b&gt; &amp; bubo</td></tr>
+    <tr><td>2</td><td>line1<br>line2</td></tr>
+    <tr><td>3</td><td></td></tr>
+  </tbody>
+</table>`,
+		},
+		{
+			msg: "classes",
+			enc: GNhtml{
+				TableClass: "names",
+				HeadClass:  "head",
+				BodyClass:  "body",
+				RowClass:   `row"`,
+			},
+			input:  []canonical{{Simple: "Aus", Full: "Aus"}},
+			format: HTML,
+			output: `<table class="names">
+  <thead class="head">
+    <tr><th>simple</th><th>canonicalFull</th></tr>
+  </thead>
+  <tbody class="body">
+    <tr class="row&#34;"><td>Aus</td><td>Aus</td></tr>
+  </tbody>
+</table>`,
+		},
+		{
+			msg:    "wrong format",
+			input:  [][]string{{"id"}},
+			format: Markdown,
+			output: "",
+		},
+	}
+
+	for _, v := range tests {
+		res := v.enc.Output(v.input, v.format)
+		assert.Equal(v.output, res, v.msg)
+	}
+}
@@ -2,7 +2,11 @@ package gnfmt
 
 import (
 	"bytes"
-	"strings"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
 
 	jsoniter "github.com/json-iterator/go"
 )
@@ -10,42 +14,205 @@ import (
 // GNjson allows to decode and encode JSON format.
 type GNjson struct {
 	Pretty bool
+
+	// Canonical makes Encode to follow RFC 8785 JSON Canonicalization
+	// Scheme: object keys are sorted, numbers are normalized and there is
+	// no insignificant whitespace. The same data always produce the same
+	// bytes, so the result can be used for hashing and signing. Canonical
+	// output is always compact, Pretty and other encoding options are
+	// ignored.
+	Canonical bool
+
+	// NoEscapeHTML keeps '<', '>' and '&' characters in strings as is.
+	// By default Encode replaces them with their \u escape sequences, to
+	// make JSON safe for embedding into HTML. Output and OutputE never
+	// escape these characters.
+	NoEscapeHTML bool
+
+	// Indent is used for indentation when Pretty is true. If it is empty,
+	// two spaces are used.
+	Indent string
+
+	// SortKeys sorts keys of maps during encoding.
+	SortKeys bool
+
+	// UseNumber decodes numbers into `any` values as json.Number instead
+	// of float64, preserving their precision.
+	UseNumber bool
+
+	// DisallowUnknownFields makes decoding to fail if an object has keys
+	// that do not match any fields of a struct.
+	DisallowUnknownFields bool
 }
 
+// jsonConfig contains settings of GNjson that require a separate
+// jsoniter configuration.
+type jsonConfig struct {
+	escapeHTML, sortKeys, useNumber, disallowUnknown bool
+}
+
+// jsonAPIs keeps frozen jsoniter configurations, they are expensive
+// to create.
+var jsonAPIs sync.Map
+
 // Encode takes an object and coverts it into JSON. It returns an error
 // if the encoding fails.
 func (e GNjson) Encode(input any) ([]byte, error) {
-	if e.Pretty {
-		return jsoniter.MarshalIndent(input, "", "  ")
+	if e.Canonical {
+		return canonicalJSON(input)
 	}
-	return jsoniter.Marshal(input)
+	res, err := e.api().Marshal(input)
+	if err != nil || !e.Pretty {
+		return res, err
+	}
+
+	indent := e.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, res, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // Decode converts JSON into a go object. If decoding breaks, it
 // returns an error.
 func (e GNjson) Decode(input []byte, output any) error {
 	r := bytes.NewReader(input)
-	err := jsoniter.NewDecoder(r).Decode(output)
+	err := e.api().NewDecoder(r).Decode(output)
+	return err
+}
+
+// EncodeTo converts an object into JSON and writes it to w followed by
+// a new line. It returns an error if the encoding or writing fails.
+func (e GNjson) EncodeTo(w io.Writer, input any) error {
+	res, err := e.Encode(input)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(res, '\n'))
 	return err
 }
 
-// Output converts an object into a JSON string. It takes an object and
-// a format and returns the corresponding JSON string. In case of a problem
-// it returns an empty string.
+// DecodeFrom reads one JSON document from r and decodes it into output.
+func (e GNjson) DecodeFrom(r io.Reader, output any) error {
+	return e.api().NewDecoder(r).Decode(output)
+}
+
+// EncodeStream writes objects from the inputs channel to w, each JSON
+// document is followed by a new line. If Pretty is false, the result
+// is in JSON Lines format.
+func (e GNjson) EncodeStream(
+	ctx context.Context,
+	w io.Writer,
+	inputs <-chan any,
+) error {
+	encode := func(input any) error {
+		return e.EncodeTo(w, input)
+	}
+	return encodeStream(ctx, inputs, encode)
+}
+
+// DecodeStream reads consecutive JSON documents from r, decodes them into
+// objects created by newOutput and sends them to the outputs channel.
+// Documents can be separated by any whitespace.
+func (e GNjson) DecodeStream(
+	ctx context.Context,
+	r io.Reader,
+	newOutput func() any,
+	outputs chan<- any,
+) (int, error) {
+	dec := e.api().NewDecoder(r)
+	decode := func(output any) error {
+		if !dec.More() {
+			return io.EOF
+		}
+		return dec.Decode(output)
+	}
+	return decodeStream(ctx, newOutput, outputs, decode)
+}
+
+// Output converts an object into a JSON string. It supports CompactJSON,
+// PrettyJSON and JSONL formats. For other formats, or in case of a
+// problem, it returns an empty string, use OutputE to find out the
+// reason.
 func (e GNjson) Output(input any, f Format) string {
+	res, _ := e.OutputE(input, f)
+	return res
+}
+
+// OutputE converts an object into a JSON string. It takes an object and
+// a format and returns the corresponding JSON string. For JSONL format
+// each element of a slice or an array is placed on its own line, other
+// objects produce one line. HTML characters are not escaped. For
+// non-JSON formats it returns ErrUnsupportedFormat.
+func (e GNjson) OutputE(input any, f Format) (string, error) {
+	e.NoEscapeHTML = true
+	var res []byte
+	var err error
 	switch f {
 	case CompactJSON:
 		e.Pretty = false
+		res, err = e.Encode(input)
 	case PrettyJSON:
 		e.Pretty = true
+		res, err = e.Encode(input)
+	case JSONL:
+		e.Pretty = false
+		res, err = e.jsonLines(input)
 	default:
-		return ""
+		return "", unsupportedFormat(f)
 	}
-	resByte, err := e.Encode(input)
 	if err != nil {
-		return ""
+		return "", err
 	}
-	res := string(resByte)
-	res = strings.ReplaceAll(res, "\\u0026", "&")
-	return res
+	return string(res), nil
+}
+
+// api returns jsoniter configuration that corresponds to the settings
+// of GNjson.
+func (e GNjson) api() jsoniter.API {
+	cfg := jsonConfig{
+		escapeHTML:      !e.NoEscapeHTML,
+		sortKeys:        e.SortKeys,
+		useNumber:       e.UseNumber,
+		disallowUnknown: e.DisallowUnknownFields,
+	}
+	if api, ok := jsonAPIs.Load(cfg); ok {
+		return api.(jsoniter.API)
+	}
+
+	api := jsoniter.Config{
+		EscapeHTML:            cfg.escapeHTML,
+		SortMapKeys:           cfg.sortKeys,
+		UseNumber:             cfg.useNumber,
+		DisallowUnknownFields: cfg.disallowUnknown,
+	}.Froze()
+	res, _ := jsonAPIs.LoadOrStore(cfg, api)
+	return res.(jsoniter.API)
+}
+
+// jsonLines converts elements of a slice or an array into JSON Lines.
+// Any other object is converted into a single line.
+func (e GNjson) jsonLines(input any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := &JSONLEncoder{w: &buf, enc: e}
+
+	v := reflect.ValueOf(input)
+	isList := v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+	if !isList || v.Type().Elem().Kind() == reflect.Uint8 {
+		if err := enc.Encode(input); err != nil {
+			return nil, err
+		}
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+
+	for i := range v.Len() {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
 }
@@ -0,0 +1,84 @@
+package gnfmt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestJSONEncodeOptions(t *testing.T) {
+	assert := assert.New(t)
+	m := map[string]string{"b": "<b>", "a": "Aus & bus"}
+	tests := []struct {
+		msg    string
+		enc    GNjson
+		input  any
+		output string
+	}{
+		{"default", GNjson{SortKeys: true}, m,
+			`{"a":"Aus \u0026 bus","b":"\u003cb\u003e"}`},
+		{"no escape", GNjson{NoEscapeHTML: true, SortKeys: true}, m,
+			`{"a":"Aus & bus","b":"<b>"}`},
+		{"indent", GNjson{Pretty: true, Indent: "\t", SortKeys: true}, m,
+			"{\n\t\"a\": \"Aus \\u0026 bus\",\n\t\"b\": \"\\u003cb\\u003e\"\n}"},
+		{"default indent", GNjson{Pretty: true}, []int{1},
+			"[\n  1\n]"},
+		{"indent no pretty", GNjson{Indent: "\t"}, []int{1}, "[1]"},
+	}
+
+	for _, v := range tests {
+		res, err := v.enc.Encode(v.input)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.output, string(res), v.msg)
+	}
+}
+
+func TestJSONOutputOptions(t *testing.T) {
+	assert := assert.New(t)
+	obj := version{Version: "<v1> & \"v2\"", Build: "a\\u0026b"}
+
+	res := GNjson{}.Output(obj, CompactJSON)
+	assert.Equal(`{"Version":"<v1> & \"v2\"","Build":"a\\u0026b"}`, res)
+
+	enc, err := GNjson{}.Encode(obj)
+	assert.Nil(err)
+	assert.Equal(
+		`{"Version":"\u003cv1\u003e \u0026 \"v2\"","Build":"a\\u0026b"}`,
+		string(enc),
+	)
+
+	res = GNjson{Indent: "    "}.Output(obj, PrettyJSON)
+	assert.Equal(
+		"{\n    \"Version\": \"<v1> & \\\"v2\\\"\",\n    \"Build\": \"a\\\\u0026b\"\n}",
+		res,
+	)
+
+	res = GNjson{}.Output([]version{obj}, JSONL)
+	assert.Equal(`{"Version":"<v1> & \"v2\"","Build":"a\\u0026b"}`, res)
+}
+
+func TestJSONDecodeOptions(t *testing.T) {
+	assert := assert.New(t)
+	input := []byte(`{"Version":"v1","Build":"today","Size":12345678901234567890}`)
+
+	var m map[string]any
+	err := GNjson{}.Decode(input, &m)
+	assert.Nil(err)
+	assert.IsType(float64(0), m["Size"])
+
+	m = nil
+	err = GNjson{UseNumber: true}.Decode(input, &m)
+	assert.Nil(err)
+	assert.Equal(json.Number("12345678901234567890"), m["Size"])
+
+	var ver version
+	err = GNjson{}.Decode(input, &ver)
+	assert.Nil(err)
+	assert.Equal("today", ver.Build)
+
+	err = GNjson{DisallowUnknownFields: true}.Decode(input, &ver)
+	assert.NotNil(err)
+}
@@ -0,0 +1,89 @@
+package gnfmt
+
+import "strings"
+
+// GNmarkdown converts structs, slices of structs or [][]string data
+// into GitHub-flavored Markdown tables. Column names for structs are
+// created the same way as for GNcsv. For [][]string data the first row is
+// a header. Pipes inside cells are escaped, new lines are replaced by
+// "<br>".
+type GNmarkdown struct{}
+
+// Output converts data into a Markdown table. For formats other than
+// Markdown, or in case of a problem, it returns an empty string, use
+// OutputE to find out the reason.
+func (e GNmarkdown) Output(record any, f Format) string {
+	res, _ := e.OutputE(record, f)
+	return res
+}
+
+// OutputE converts data into a Markdown table. For formats other than
+// Markdown it returns ErrUnsupportedFormat.
+func (e GNmarkdown) OutputE(record any, f Format) (string, error) {
+	if f != Markdown {
+		return "", unsupportedFormat(f)
+	}
+	tbl, err := tabulate(record)
+	if err != nil {
+		return "", err
+	}
+	if len(tbl.header) == 0 {
+		return "", errNoColumns
+	}
+
+	header := escapeMarkdownRow(tbl.header)
+	rows := make([][]string, len(tbl.rows))
+	for i, v := range tbl.rows {
+		rows[i] = escapeMarkdownRow(NormRowSize(v, len(header)))
+	}
+
+	widths := make([]int, len(header))
+	for i, v := range header {
+		widths[i] = max(3, displayWidth(v))
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			widths[i] = max(widths[i], displayWidth(v))
+		}
+	}
+
+	sep := make([]string, len(header))
+	for i, w := range widths {
+		sep[i] = strings.Repeat("-", w)
+	}
+
+	var b strings.Builder
+	writeMarkdownRow(&b, header, widths)
+	b.WriteByte('\n')
+	writeMarkdownRow(&b, sep, widths)
+	for _, row := range rows {
+		b.WriteByte('\n')
+		writeMarkdownRow(&b, row, widths)
+	}
+	return b.String(), nil
+}
+
+func writeMarkdownRow(b *strings.Builder, row []string, widths []int) {
+	b.WriteByte('|')
+	for i, v := range row {
+		b.WriteByte(' ')
+		b.WriteString(v)
+		b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(v)))
+		b.WriteString(" |")
+	}
+}
+
+var markdownReplacer = strings.NewReplacer(
+	"|", `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+	"\r", "<br>",
+)
+
+func escapeMarkdownRow(row []string) []string {
+	res := make([]string, len(row))
+	for i, v := range row {
+		res[i] = markdownReplacer.Replace(v)
+	}
+	return res
+}
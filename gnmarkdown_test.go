@@ -0,0 +1,70 @@
+package gnfmt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestMarkdownOutput(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg    string
+		input  any
+		format Format
+		output string
+	}{
+		{
+			msg: "structs",
+			input: []canonical{
+				{Simple: "Bubo bubo", Full: "Bubo bubo"},
+				{Simple: "Aus", Full: "Aus var. bus"},
+			},
+			format: Markdown,
+			output: "| simple    | canonicalFull |\n" +
+				"| --------- | ------------- |\n" +
+				"| Bubo bubo | Bubo bubo     |\n" +
+				"| Aus       | Aus var. bus  |",
+		},
+		{
+			msg: "rows",
+			input: [][]string{
+				{"id", "name"},
+				{"1", "a|b"},
+				{"2", "line1\nline2"},
+				{"3"},
+			},
+			format: Markdown,
+			output: "| id  | name           |\n" +
+				"| --- | -------------- |\n" +
+				"| 1   | a\\|b           |\n" +
+				"| 2   | line1<br>line2 |\n" +
+				"| 3   |                |",
+		},
+		{
+			msg:    "unicode",
+			input:  [][]string{{"name"}, {"Aé"}},
+			format: Markdown,
+			output: "| name |\n| ---- |\n| Aé   |",
+		},
+		{
+			msg:    "wrong format",
+			input:  [][]string{{"id"}},
+			format: CSV,
+			output: "",
+		},
+		{
+			msg:    "no header",
+			input:  [][]string{},
+			format: Markdown,
+			output: "",
+		},
+	}
+
+	for _, v := range tests {
+		res := GNmarkdown{}.Output(v.input, v.format)
+		assert.Equal(v.output, res, v.msg)
+	}
+}
@@ -0,0 +1,79 @@
+package gnfmt
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// GNmsgpack allows to encode and decode MessagePack format. Names of
+// struct fields are taken from `msgpack` tags. If a field does not have
+// such a tag, its `json` tag is used instead.
+//
+// MessagePack is a binary format, so GNmsgpack does not implement
+// the Outputter interface.
+type GNmsgpack struct{}
+
+// Encode takes an object and converts it into MessagePack bytes. It
+// returns an error if the encoding fails.
+func (e GNmsgpack) Encode(input any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := e.newEncoder(&buf).Encode(input); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode converts MessagePack bytes into a Go object. If decoding breaks,
+// it returns an error.
+func (e GNmsgpack) Decode(input []byte, output any) error {
+	return e.newDecoder(bytes.NewReader(input)).Decode(output)
+}
+
+// EncodeTo converts an object into MessagePack and writes it to w.
+func (e GNmsgpack) EncodeTo(w io.Writer, input any) error {
+	return e.newEncoder(w).Encode(input)
+}
+
+// DecodeFrom reads one MessagePack object from r and decodes it into
+// output.
+func (e GNmsgpack) DecodeFrom(r io.Reader, output any) error {
+	return e.newDecoder(r).Decode(output)
+}
+
+// EncodeStream writes objects from the inputs channel to w as consecutive
+// MessagePack objects.
+func (e GNmsgpack) EncodeStream(
+	ctx context.Context,
+	w io.Writer,
+	inputs <-chan any,
+) error {
+	enc := e.newEncoder(w)
+	return encodeStream(ctx, inputs, enc.Encode)
+}
+
+// DecodeStream reads consecutive MessagePack objects from r, decodes them
+// into objects created by newOutput and sends them to the outputs channel.
+func (e GNmsgpack) DecodeStream(
+	ctx context.Context,
+	r io.Reader,
+	newOutput func() any,
+	outputs chan<- any,
+) (int, error) {
+	dec := e.newDecoder(r)
+	return decodeStream(ctx, newOutput, outputs, dec.Decode)
+}
+
+func (e GNmsgpack) newEncoder(w io.Writer) *msgpack.Encoder {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc
+}
+
+func (e GNmsgpack) newDecoder(r io.Reader) *msgpack.Decoder {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec
+}
@@ -0,0 +1,35 @@
+package gnfmt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestMsgpackTags(t *testing.T) {
+	assert := assert.New(t)
+	type tagged struct {
+		Name    string `msgpack:"n"`
+		Year    int    `json:"year"`
+		Skip    string `json:"-"`
+		Comment string `msgpack:"comment,omitempty"`
+	}
+	enc := GNmsgpack{}
+	obj := tagged{Name: "Bubo bubo", Year: 1758, Skip: "skip"}
+	res, err := enc.Encode(obj)
+	assert.Nil(err)
+
+	var m map[string]any
+	err = enc.Decode(res, &m)
+	assert.Nil(err)
+	assert.Len(m, 2)
+	assert.Equal("Bubo bubo", m["n"])
+	assert.EqualValues(1758, m["year"])
+
+	var obj2 tagged
+	err = enc.Decode(res, &obj2)
+	assert.Nil(err)
+	assert.Equal(tagged{Name: "Bubo bubo", Year: 1758}, obj2)
+}
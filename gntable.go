@@ -0,0 +1,236 @@
+package gnfmt
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// minColumnWidth is the narrowest width a column can be shrunk to when
+// a table is fitted into a terminal.
+const minColumnWidth = 4
+
+// columnGap separates columns of a table.
+const columnGap = "  "
+
+// ellipsis marks truncated cells.
+const ellipsis = "…"
+
+// widthCond measures the width of strings in terminal cells. Wide East
+// Asian characters take two cells, characters of ambiguous width take
+// one cell regardless of the locale.
+var widthCond = func() *runewidth.Condition {
+	res := runewidth.NewCondition()
+	res.EastAsianWidth = false
+	return res
+}()
+
+// displayWidth returns the number of terminal cells a string occupies.
+func displayWidth(s string) int {
+	return widthCond.StringWidth(s)
+}
+
+// GNtable converts structs, slices of structs or [][]string data into
+// a plain-text table with aligned columns for terminal display. Column
+// names for structs are created the same way as for GNcsv. For [][]string
+// data the first row is a header. The width of cells is measured in
+// terminal cells, so wide East Asian characters take two positions.
+type GNtable struct {
+	// MaxCellWidth truncates cells that are wider than the given number of
+	// terminal cells, the end of a truncated cell is replaced with "…".
+	// If it is zero, cells are not truncated.
+	MaxCellWidth int
+
+	// Width is the width of a terminal. If it is greater than zero, the
+	// widest columns are narrowed until the table fits into the width,
+	// and the text of their cells is wrapped to several lines. The
+	// TerminalWidth function returns the width of the current terminal.
+	Width int
+}
+
+// Output converts data into a text table. For formats other than Table,
+// or in case of a problem, it returns an empty string, use OutputE to
+// find out the reason.
+func (e GNtable) Output(record any, f Format) string {
+	res, _ := e.OutputE(record, f)
+	return res
+}
+
+// OutputE converts data into a text table. For formats other than Table
+// it returns ErrUnsupportedFormat.
+func (e GNtable) OutputE(record any, f Format) (string, error) {
+	if f != Table {
+		return "", unsupportedFormat(f)
+	}
+	tbl, err := tabulate(record)
+	if err != nil {
+		return "", err
+	}
+	if len(tbl.header) == 0 {
+		return "", errNoColumns
+	}
+
+	rows := make([][]string, 0, len(tbl.rows)+1)
+	rows = append(rows, e.prepareRow(tbl.header, len(tbl.header)))
+	for _, v := range tbl.rows {
+		rows = append(rows, e.prepareRow(v, len(tbl.header)))
+	}
+
+	widths := make([]int, len(tbl.header))
+	for _, row := range rows {
+		for i, v := range row {
+			for line := range strings.SplitSeq(v, "\n") {
+				widths[i] = max(widths[i], displayWidth(line))
+			}
+		}
+	}
+	if e.Width > 0 {
+		fitWidths(widths, e.Width)
+	}
+
+	sep := make([]string, len(widths))
+	for i, w := range widths {
+		sep[i] = strings.Repeat("-", w)
+	}
+
+	var b strings.Builder
+	writeTableRow(&b, rows[0], widths)
+	writeTableRow(&b, sep, widths)
+	for _, row := range rows[1:] {
+		writeTableRow(&b, row, widths)
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// prepareRow normalizes the size of a row, removes tabs and carriage
+// returns, and truncates cells that are too wide.
+func (e GNtable) prepareRow(row []string, size int) []string {
+	res := make([]string, size)
+	copy(res, row)
+	for i, v := range res {
+		v = strings.ReplaceAll(v, "\r\n", "\n")
+		v = strings.ReplaceAll(v, "\r", "\n")
+		v = strings.ReplaceAll(v, "\t", " ")
+		if e.MaxCellWidth > 0 {
+			lines := strings.Split(v, "\n")
+			for j, line := range lines {
+				lines[j] = truncateCell(line, e.MaxCellWidth)
+			}
+			v = strings.Join(lines, "\n")
+		}
+		res[i] = v
+	}
+	return res
+}
+
+// truncateCell cuts a string to the given width, replacing the end with
+// an ellipsis.
+func truncateCell(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	return widthCond.Truncate(s, width, ellipsis)
+}
+
+// fitWidths narrows the widest columns until the total width of a table
+// fits into the given width, or until all columns reach minColumnWidth.
+func fitWidths(widths []int, total int) {
+	avail := total - len(columnGap)*(len(widths)-1)
+	for {
+		var sum, widest int
+		for i, w := range widths {
+			sum += w
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if sum <= avail || widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+// writeTableRow writes a row of cells, wrapping them to the widths of
+// their columns. A row might take several lines.
+func writeTableRow(b *strings.Builder, row []string, widths []int) {
+	cells := make([][]string, len(row))
+	var height int
+	for i, v := range row {
+		cells[i] = wrapCell(v, widths[i])
+		height = max(height, len(cells[i]))
+	}
+
+	for l := range height {
+		var line strings.Builder
+		for i, cell := range cells {
+			if i > 0 {
+				line.WriteString(columnGap)
+			}
+			var s string
+			if l < len(cell) {
+				s = cell[l]
+			}
+			line.WriteString(s)
+			line.WriteString(strings.Repeat(" ", max(0, widths[i]-displayWidth(s))))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+}
+
+// wrapCell splits the text of a cell into lines that fit into the width.
+// Lines that already fit are kept as is, others are broken at spaces,
+// and words that are longer than the width are broken at the width.
+func wrapCell(s string, width int) []string {
+	var res []string
+	for para := range strings.SplitSeq(s, "\n") {
+		if displayWidth(para) <= width {
+			res = append(res, para)
+			continue
+		}
+
+		var line string
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = word
+			case displayWidth(line)+1+displayWidth(word) <= width:
+				line += " " + word
+				continue
+			default:
+				res = append(res, line)
+				line = word
+			}
+			for displayWidth(line) > width {
+				head := widthCond.Truncate(line, width, "")
+				if head == "" {
+					// a character wider than the column
+					_, size := utf8.DecodeRuneInString(line)
+					head = line[:size]
+				}
+				res = append(res, head)
+				line = line[len(head):]
+			}
+		}
+		res = append(res, line)
+	}
+	return res
+}
+
+// TerminalWidth returns the width of the terminal attached to the
+// standard output. If the standard output is not a terminal, it uses the
+// COLUMNS environment variable. It returns 0 if the width is unknown.
+func TerminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
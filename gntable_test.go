@@ -0,0 +1,106 @@
+package gnfmt_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestTableOutput(t *testing.T) {
+	assert := assert.New(t)
+	rows := [][]string{
+		{"id", "name", "note"},
+		{"1", "Bubo bubo", ""},
+		{"2", "Aus bus cus", "a\tb"},
+		{"3", "日本語"},
+	}
+
+	tests := []struct {
+		msg    string
+		enc    GNtable
+		input  any
+		format Format
+		output []string
+	}{
+		{
+			msg:    "aligned",
+			input:  rows,
+			format: Table,
+			output: []string{
+				"id  name         note",
+				"--  -----------  ----",
+				"1   Bubo bubo",
+				"2   Aus bus cus  a b",
+				"3   日本語",
+			},
+		},
+		{
+			msg:    "truncate",
+			enc:    GNtable{MaxCellWidth: 5},
+			input:  rows,
+			format: Table,
+			output: []string{
+				"id  name   note",
+				"--  -----  ----",
+				"1   Bubo…",
+				"2   Aus …  a b",
+				"3   日本…",
+			},
+		},
+		{
+			msg:    "wrap",
+			enc:    GNtable{Width: 16},
+			input:  rows,
+			format: Table,
+			output: []string{
+				"id  name    note",
+				"--  ------  ----",
+				"1   Bubo",
+				"    bubo",
+				"2   Aus     a b",
+				"    bus",
+				"    cus",
+				"3   日本語",
+			},
+		},
+		{
+			msg: "structs",
+			input: []canonical{
+				{Simple: "Bubo bubo", Full: "Bubo bubo"},
+				{Simple: "Aus", Full: "Aus var. bus"},
+			},
+			format: Table,
+			output: []string{
+				"simple     canonicalFull",
+				"---------  -------------",
+				"Bubo bubo  Bubo bubo",
+				"Aus        Aus var. bus",
+			},
+		},
+		{
+			msg:    "spaces",
+			input:  [][]string{{"a", "b"}, {"x  y", "z"}, {" w", "v"}},
+			format: Table,
+			output: []string{
+				"a     b",
+				"----  -",
+				"x  y  z",
+				" w    v",
+			},
+		},
+		{
+			msg:    "wrong format",
+			input:  rows,
+			format: TSV,
+			output: []string{""},
+		},
+	}
+
+	for _, v := range tests {
+		res := v.enc.Output(v.input, v.format)
+		assert.Equal(strings.Join(v.output, "\n"), res, v.msg)
+	}
+}
@@ -0,0 +1,159 @@
+package gnfmt
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// GNxml allows to encode and decode XML format using `xml` struct tags
+// of the encoding/xml package. Slices and arrays are encoded as a root
+// element that contains one element for every item.
+type GNxml struct {
+	// Root is the name of the root element. For slices it defaults to
+	// "records". For other objects, if Root is empty, the name is taken
+	// from the XMLName field or from the name of the type.
+	Root string
+
+	// Record is the name of elements created for items of slices. It
+	// defaults to "record".
+	Record string
+
+	// Pretty adds new lines and indentation to the output.
+	Pretty bool
+}
+
+// Encode takes an object and converts it into an XML document with
+// an XML declaration. It returns an error if the encoding fails.
+func (e GNxml) Encode(input any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if e.Pretty {
+		enc.Indent("", "  ")
+	}
+
+	v := reflect.ValueOf(input)
+	var err error
+	switch {
+	case isXMLList(v):
+		err = e.encodeList(enc, v)
+	case e.Root != "":
+		err = enc.EncodeElement(input, xmlStart(e.Root))
+	default:
+		err = enc.Encode(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode converts XML into a Go object. If output is a pointer to
+// a slice, every child element of the root is decoded into a new item of
+// the slice. If decoding breaks, it returns an error.
+func (e GNxml) Decode(input []byte, output any) error {
+	dec := xml.NewDecoder(bytes.NewReader(input))
+	v := reflect.ValueOf(output)
+	if v.Kind() != reflect.Pointer || v.IsNil() || !isXMLList(v.Elem()) ||
+		v.Elem().Kind() != reflect.Slice {
+		return dec.Decode(output)
+	}
+	return decodeXMLList(dec, v.Elem())
+}
+
+// Output converts an object into an XML string. For formats other than
+// XML, or in case of a problem, it returns an empty string, use OutputE
+// to find out the reason.
+func (e GNxml) Output(input any, f Format) string {
+	res, _ := e.OutputE(input, f)
+	return res
+}
+
+// OutputE converts an object into an XML string. For formats other than
+// XML it returns ErrUnsupportedFormat.
+func (e GNxml) OutputE(input any, f Format) (string, error) {
+	if f != XML {
+		return "", unsupportedFormat(f)
+	}
+	res, err := e.Encode(input)
+	if err != nil {
+		return "", err
+	}
+	return string(res), nil
+}
+
+func (e GNxml) encodeList(enc *xml.Encoder, v reflect.Value) error {
+	root := xmlStart(e.Root)
+	if e.Root == "" {
+		root = xmlStart("records")
+	}
+	item := xmlStart(e.Record)
+	if e.Record == "" {
+		item = xmlStart("record")
+	}
+
+	if err := enc.EncodeToken(root); err != nil {
+		return err
+	}
+	for i := range v.Len() {
+		if err := enc.EncodeElement(v.Index(i).Interface(), item); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(root.End())
+}
+
+// decodeXMLList decodes children of the root element into new items
+// of a slice.
+func decodeXMLList(dec *xml.Decoder, slice reflect.Value) error {
+	var depth int
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			if depth > 0 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				depth++
+				continue
+			}
+			item := reflect.New(slice.Type().Elem())
+			if err = dec.DecodeElement(item.Interface(), &t); err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, item.Elem()))
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// isXMLList returns true for slices and arrays, except byte slices that
+// encoding/xml treats as text.
+func isXMLList(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false
+	}
+	return v.Type().Elem().Kind() != reflect.Uint8
+}
+
+func xmlStart(name string) xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: name}}
+}
@@ -0,0 +1,97 @@
+package gnfmt_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+type xmlName struct {
+	ID        int      `xml:"id,attr"`
+	Canonical string   `xml:"canonical"`
+	Authors   []string `xml:"author"`
+}
+
+func TestXMLOutput(t *testing.T) {
+	assert := assert.New(t)
+	names := []xmlName{
+		{ID: 1, Canonical: "Bubo bubo", Authors: []string{"Linnaeus"}},
+		{ID: 2, Canonical: "Aus & bus"},
+	}
+
+	tests := []struct {
+		msg    string
+		enc    GNxml
+		input  any
+		format Format
+		output string
+	}{
+		{
+			msg:    "slice",
+			input:  names,
+			format: XML,
+			output: xml.Header + `<records><record id="1"><canonical>Bubo bubo</canonical>` +
+				`<author>Linnaeus</author></record>` +
+				`<record id="2"><canonical>Aus &amp; bus</canonical></record></records>`,
+		},
+		{
+			msg:    "pretty names",
+			enc:    GNxml{Root: "names", Record: "name", Pretty: true},
+			input:  names[1:],
+			format: XML,
+			output: xml.Header + `<names>
+  <name id="2">
+    <canonical>Aus &amp; bus</canonical>
+  </name>
+</names>`,
+		},
+		{
+			msg:    "struct",
+			input:  names[1],
+			format: XML,
+			output: xml.Header +
+				`<xmlName id="2"><canonical>Aus &amp; bus</canonical></xmlName>`,
+		},
+		{
+			msg:    "struct root",
+			enc:    GNxml{Root: "name"},
+			input:  names[1],
+			format: XML,
+			output: xml.Header +
+				`<name id="2"><canonical>Aus &amp; bus</canonical></name>`,
+		},
+		{
+			msg:    "wrong format",
+			input:  names,
+			format: CompactJSON,
+			output: "",
+		},
+	}
+
+	for _, v := range tests {
+		res := v.enc.Output(v.input, v.format)
+		assert.Equal(v.output, res, v.msg)
+	}
+}
+
+func TestXMLDecodeSlice(t *testing.T) {
+	assert := assert.New(t)
+	names := []xmlName{
+		{ID: 1, Canonical: "Bubo bubo", Authors: []string{"Linnaeus"}},
+		{ID: 2, Canonical: "Aus & bus"},
+	}
+	enc := GNxml{Root: "names", Record: "name", Pretty: true}
+	res, err := enc.Encode(names)
+	assert.Nil(err)
+
+	var names2 []xmlName
+	err = GNxml{}.Decode(res, &names2)
+	assert.Nil(err)
+	assert.Equal(names, names2)
+
+	err = GNxml{}.Decode([]byte("<names><name id=\"1\">"), &names2)
+	assert.NotNil(err)
+}
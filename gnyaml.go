@@ -0,0 +1,98 @@
+package gnfmt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GNyaml allows to decode and encode YAML format. Names of struct fields
+// are taken from `yaml` tags, fields without tags use lowercased field
+// names.
+type GNyaml struct{}
+
+// Encode takes an object and converts it into YAML. It returns an error
+// if the encoding fails.
+func (e GNyaml) Encode(input any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := e.EncodeTo(&buf, input); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode converts YAML into a Go object. If decoding breaks, it returns
+// an error.
+func (e GNyaml) Decode(input []byte, output any) error {
+	return yaml.Unmarshal(input, output)
+}
+
+// EncodeTo converts an object into a YAML document and writes it to w.
+func (e GNyaml) EncodeTo(w io.Writer, input any) error {
+	enc := newYAMLEncoder(w)
+	if err := enc.Encode(input); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// DecodeFrom reads one YAML document from r and decodes it into output.
+func (e GNyaml) DecodeFrom(r io.Reader, output any) error {
+	return yaml.NewDecoder(r).Decode(output)
+}
+
+// EncodeStream writes objects from the inputs channel to w as a YAML
+// stream, documents are separated by "---".
+func (e GNyaml) EncodeStream(
+	ctx context.Context,
+	w io.Writer,
+	inputs <-chan any,
+) error {
+	enc := newYAMLEncoder(w)
+	if err := encodeStream(ctx, inputs, enc.Encode); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// DecodeStream reads documents of a YAML stream from r, decodes them into
+// objects created by newOutput and sends them to the outputs channel.
+func (e GNyaml) DecodeStream(
+	ctx context.Context,
+	r io.Reader,
+	newOutput func() any,
+	outputs chan<- any,
+) (int, error) {
+	dec := yaml.NewDecoder(r)
+	return decodeStream(ctx, newOutput, outputs, dec.Decode)
+}
+
+// Output converts an object into a YAML string. For formats other than
+// YAML, or in case of a problem, it returns an empty string, use OutputE
+// to find out the reason.
+func (e GNyaml) Output(input any, f Format) string {
+	res, _ := e.OutputE(input, f)
+	return res
+}
+
+// OutputE converts an object into a YAML string. For formats other than
+// YAML it returns ErrUnsupportedFormat.
+func (e GNyaml) OutputE(input any, f Format) (string, error) {
+	if f != YAML {
+		return "", unsupportedFormat(f)
+	}
+	res, err := e.Encode(input)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(res), "\n"), nil
+}
+
+func newYAMLEncoder(w io.Writer) *yaml.Encoder {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	return enc
+}
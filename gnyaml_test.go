@@ -0,0 +1,53 @@
+package gnfmt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestYAMLOutput(t *testing.T) {
+	assert := assert.New(t)
+	type build struct {
+		Version string   `yaml:"version"`
+		Tags    []string `yaml:"tags,omitempty"`
+		Date    string
+	}
+
+	tests := []struct {
+		msg    string
+		input  any
+		format Format
+		output string
+	}{
+		{
+			msg: "yaml",
+			input: build{
+				Version: "v1.0.0",
+				Tags:    []string{"one", "two"},
+				Date:    "today",
+			},
+			format: YAML,
+			output: "version: v1.0.0\ntags:\n  - one\n  - two\ndate: today",
+		},
+		{
+			msg:    "omitempty",
+			input:  build{Version: "v1.0.0"},
+			format: YAML,
+			output: "version: v1.0.0\ndate: \"\"",
+		},
+		{
+			msg:    "json",
+			input:  build{Version: "v1.0.0"},
+			format: CompactJSON,
+			output: "",
+		},
+	}
+
+	for _, v := range tests {
+		res := GNyaml{}.Output(v.input, v.format)
+		assert.Equal(v.output, res, v.msg)
+	}
+}
@@ -0,0 +1,75 @@
+package gnfmt
+
+import (
+	"encoding/gob"
+	"io"
+)
+
+// GobWriter is a gob encoding session bound to an io.Writer. Unlike
+// GNgob.Encode, that creates a new encoder for every object, GobWriter
+// sends the description of a type only once, when an object of this type
+// is encoded for the first time. All following objects of the same type
+// carry only their values. Use GobReader to decode the stream.
+type GobWriter struct {
+	enc   *gob.Encoder
+	count int
+}
+
+// NewGobWriter creates a new gob encoding session that writes to w.
+// If w is buffered, it is the responsibility of the caller to flush it.
+func NewGobWriter(w io.Writer) *GobWriter {
+	return &GobWriter{enc: gob.NewEncoder(w)}
+}
+
+// Encode serializes an object and writes it to the underlying writer.
+func (g *GobWriter) Encode(input any) error {
+	if err := g.enc.Encode(input); err != nil {
+		return err
+	}
+	g.count++
+	return nil
+}
+
+// Count returns the number of objects encoded during the session.
+func (g *GobWriter) Count() int {
+	return g.count
+}
+
+// GobReader is a gob decoding session bound to an io.Reader. It reads
+// streams created by GobWriter, remembering type descriptions received
+// earlier in the stream.
+type GobReader struct {
+	dec   *gob.Decoder
+	count int
+}
+
+// NewGobReader creates a new gob decoding session that reads from r.
+func NewGobReader(r io.Reader) *GobReader {
+	return &GobReader{dec: gob.NewDecoder(r)}
+}
+
+// Decode reads the next object from the stream and deserializes it into
+// output. It returns io.EOF when the stream has no more objects.
+func (g *GobReader) Decode(output any) error {
+	if err := g.dec.Decode(output); err != nil {
+		return err
+	}
+	g.count++
+	return nil
+}
+
+// Count returns the number of objects decoded during the session.
+func (g *GobReader) Count() int {
+	return g.count
+}
+
+// RegisterGob registers concrete types of the given values with the gob
+// package. Registration is required when objects are sent as interface
+// values, for example as elements of []any or values of map[string]any.
+// It panics if the same name is used for different types, the same way
+// as gob.Register does.
+func RegisterGob(values ...any) {
+	for _, v := range values {
+		gob.Register(v)
+	}
+}
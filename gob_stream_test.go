@@ -0,0 +1,140 @@
+package gnfmt_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+type cacheRecord struct {
+	ID       int
+	Name     string
+	Cardinal int
+	Words    []string
+	Extra    any
+}
+
+type extra struct {
+	Note string
+}
+
+func TestGobSession(t *testing.T) {
+	assert := assert.New(t)
+	RegisterGob(extra{})
+
+	recs := []cacheRecord{
+		{ID: 1, Name: "Bubo bubo", Cardinal: 2, Words: []string{"Bubo", "bubo"}},
+		{ID: 2, Name: "Aus", Cardinal: 1, Words: []string{"Aus"}},
+		{ID: 3, Name: "Bus cus", Cardinal: 2, Extra: extra{Note: "note"}},
+	}
+
+	var buf bytes.Buffer
+	w := NewGobWriter(&buf)
+	for _, v := range recs {
+		err := w.Encode(v)
+		assert.Nil(err)
+	}
+	assert.Equal(3, w.Count())
+
+	// type information is sent only once
+	var perCall int
+	for _, v := range recs {
+		res, err := GNgob{}.Encode(v)
+		assert.Nil(err)
+		perCall += len(res)
+	}
+	assert.Less(buf.Len(), perCall)
+
+	r := NewGobReader(&buf)
+	var res []cacheRecord
+	for {
+		var rec cacheRecord
+		err := r.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.Nil(err)
+		res = append(res, rec)
+	}
+	assert.Equal(3, r.Count())
+	assert.Equal(recs, res)
+}
+
+func BenchmarkGobEncode(b *testing.B) {
+	rec := cacheRecord{
+		ID:       1,
+		Name:     "Bubo bubo (Linnaeus, 1758)",
+		Cardinal: 2,
+		Words:    []string{"Bubo", "bubo", "Linnaeus", "1758"},
+	}
+
+	b.Run("GNgob per call", func(b *testing.B) {
+		var size int
+		enc := GNgob{}
+		for b.Loop() {
+			res, err := enc.Encode(rec)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size += len(res)
+		}
+		b.ReportMetric(float64(size)/float64(b.N), "bytes/rec")
+	})
+
+	b.Run("GobWriter session", func(b *testing.B) {
+		var buf bytes.Buffer
+		enc := NewGobWriter(&buf)
+		for b.Loop() {
+			if err := enc.Encode(rec); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(buf.Len())/float64(b.N), "bytes/rec")
+	})
+}
+
+func BenchmarkGobDecode(b *testing.B) {
+	rec := cacheRecord{
+		ID:       1,
+		Name:     "Bubo bubo (Linnaeus, 1758)",
+		Cardinal: 2,
+		Words:    []string{"Bubo", "bubo", "Linnaeus", "1758"},
+	}
+
+	b.Run("GNgob per call", func(b *testing.B) {
+		enc := GNgob{}
+		blob, err := enc.Encode(rec)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for b.Loop() {
+			var res cacheRecord
+			if err = enc.Decode(blob, &res); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("GobReader session", func(b *testing.B) {
+		var buf bytes.Buffer
+		enc := NewGobWriter(&buf)
+		for range b.N {
+			if err := enc.Encode(rec); err != nil {
+				b.Fatal(err)
+			}
+		}
+		dec := NewGobReader(&buf)
+		b.ResetTimer()
+		for range b.N {
+			var res cacheRecord
+			if err := dec.Decode(&res); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
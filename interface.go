@@ -1,5 +1,10 @@
 package gnfmt
 
+import (
+	"context"
+	"io"
+)
+
 // Outputter interface us a uniform way to create an output of a datum
 type Outputter interface {
 	// FormattedOutput takes a record and returns a string representation of
@@ -7,6 +12,17 @@ type Outputter interface {
 	Output(record any, f Format) string
 }
 
+// OutputterE is an Outputter that is able to report why an output could
+// not be created.
+type OutputterE interface {
+	Outputter
+
+	// OutputE takes a record and returns its string representation
+	// according to the supplied format. If the format is not supported,
+	// the error wraps ErrUnsupportedFormat.
+	OutputE(record any, f Format) (string, error)
+}
+
 // Encoder interface allows to switch between different encoding types.
 type Encoder interface {
 	//Encode takes a Go object and converts it into bytes
@@ -14,3 +30,31 @@ type Encoder interface {
 	// Decode takes an input of bytes and decodes it into Go object.
 	Decode(input []byte, output any) error
 }
+
+// StreamEncoder interface allows to encode and decode data directly to and
+// from streams, without keeping the whole payload in memory.
+type StreamEncoder interface {
+	// EncodeTo takes a Go object, encodes it and writes the result to w.
+	EncodeTo(w io.Writer, input any) error
+
+	// DecodeFrom reads one encoded object from r and decodes it into
+	// the output.
+	DecodeFrom(r io.Reader, output any) error
+
+	// EncodeStream encodes all objects received from the inputs channel
+	// and writes them to w as one stream. It stops when the channel is
+	// closed, or when the context is canceled.
+	EncodeStream(ctx context.Context, w io.Writer, inputs <-chan any) error
+
+	// DecodeStream decodes a stream created by EncodeStream. For every
+	// object in the stream it creates a new output with newOutput function,
+	// decodes the object into it, and sends the output to the outputs
+	// channel. It returns the number of decoded objects. The outputs
+	// channel is not closed by DecodeStream.
+	DecodeStream(
+		ctx context.Context,
+		r io.Reader,
+		newOutput func() any,
+		outputs chan<- any,
+	) (int, error)
+}
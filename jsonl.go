@@ -0,0 +1,76 @@
+package gnfmt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// JSONLEncoder writes values to an io.Writer in JSON Lines (NDJSON)
+// format, one compact JSON document per line.
+type JSONLEncoder struct {
+	w   io.Writer
+	enc GNjson
+}
+
+// NewJSONLEncoder creates a JSON Lines encoder that writes to w.
+func NewJSONLEncoder(w io.Writer) *JSONLEncoder {
+	return &JSONLEncoder{w: w}
+}
+
+// Encode converts a value into compact JSON and writes it to the
+// underlying writer followed by a new line.
+func (e *JSONLEncoder) Encode(input any) error {
+	res, err := e.enc.Encode(input)
+	if err != nil {
+		return err
+	}
+	res = append(res, '\n')
+	_, err = e.w.Write(res)
+	return err
+}
+
+// JSONLDecoder reads JSON Lines (NDJSON) from an io.Reader, one JSON
+// document per line. Empty lines are ignored.
+type JSONLDecoder struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewJSONLDecoder creates a JSON Lines decoder that reads from r.
+func NewJSONLDecoder(r io.Reader) *JSONLDecoder {
+	return &JSONLDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next line and decodes it into output. It returns
+// io.EOF when there are no more lines to read. Decoding errors contain
+// the number of the line that failed.
+func (d *JSONLDecoder) Decode(output any) error {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		d.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if errors.Is(err, io.EOF) {
+				return io.EOF
+			}
+			continue
+		}
+
+		if err = jsoniter.Unmarshal(line, output); err != nil {
+			return fmt.Errorf("line %d: %w", d.line, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package gnfmt_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestJSONL(t *testing.T) {
+	assert := assert.New(t)
+	vers := []version{
+		{Version: "v1.0.0", Build: "one\ntwo"},
+		{Version: "v1.0.1", Build: "three"},
+	}
+
+	var buf bytes.Buffer
+	enc := NewJSONLEncoder(&buf)
+	for _, v := range vers {
+		err := enc.Encode(v)
+		assert.Nil(err)
+	}
+	assert.Equal(
+		`{"Version":"v1.0.0","Build":"one\ntwo"}`+"\n"+
+			`{"Version":"v1.0.1","Build":"three"}`+"\n",
+		buf.String(),
+	)
+
+	var res []version
+	dec := NewJSONLDecoder(&buf)
+	for {
+		var ver version
+		err := dec.Decode(&ver)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.Nil(err)
+		res = append(res, ver)
+	}
+	assert.Equal(vers, res)
+}
+
+func TestJSONLDecodeErr(t *testing.T) {
+	assert := assert.New(t)
+	input := "{\"Version\":\"v1\"}\n\n  \n{\"Version\":\"v2\"}\n{bad}\n"
+	dec := NewJSONLDecoder(strings.NewReader(input))
+
+	var ver version
+	assert.Nil(dec.Decode(&ver))
+	assert.Equal("v1", ver.Version)
+	assert.Nil(dec.Decode(&ver))
+	assert.Equal("v2", ver.Version)
+
+	err := dec.Decode(&ver)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "line 5")
+}
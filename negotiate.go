@@ -0,0 +1,207 @@
+package gnfmt
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNotAcceptable means that none of the supported formats satisfies
+// the Accept header of a request.
+var ErrNotAcceptable = errors.New("no acceptable format")
+
+// mediaRange is one element of an Accept header, for example
+// "text/csv;q=0.8".
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// NegotiateFormat picks the best format for the given Accept header. It
+// takes quality values ("q=0.5") into account and, when several formats
+// have the same quality, prefers the one that goes earlier in the
+// supported list. If the supported list is empty, all registered formats
+// that have a MIME type and an Outputter are used, with CompactJSON
+// preferred. An empty Accept header accepts anything.
+//
+// It returns the format and a matching value for the Content-Type header.
+// If nothing is acceptable, it returns ErrNotAcceptable.
+func NegotiateFormat(accept string, supported ...Format) (Format, string, error) {
+	if len(supported) == 0 {
+		supported = outputFormats()
+	}
+	ranges := parseAccept(accept)
+
+	var res Format
+	var bestQ float64
+	for _, f := range supported {
+		mime := f.MIMEType()
+		if mime == "" {
+			continue
+		}
+		if q := acceptQuality(ranges, mime); q > bestQ {
+			res, bestQ = f, q
+		}
+	}
+
+	if res == FormatNone {
+		return FormatNone, "", ErrNotAcceptable
+	}
+	return res, ContentType(res), nil
+}
+
+// ContentType returns a value for the Content-Type header of a format.
+// Text formats get "charset=utf-8" parameter. It returns an empty string
+// if the format does not have a MIME type.
+func ContentType(f Format) string {
+	mime := f.MIMEType()
+	if mime == "" {
+		return ""
+	}
+	if strings.HasPrefix(mime, "text/") ||
+		strings.Contains(mime, "json") ||
+		strings.Contains(mime, "yaml") ||
+		strings.Contains(mime, "xml") {
+		return mime + "; charset=utf-8"
+	}
+	return mime
+}
+
+// OutputHandler creates an http.Handler that obtains a value from fn and
+// writes it in a format negotiated from the Accept header of the request.
+// The value is rendered by the Outputter of the format. If the supported
+// list is empty, all registered formats are used.
+//
+// The handler responds with 406 Not Acceptable if no format fits the
+// request, and with 500 Internal Server Error if fn returns an error or
+// the value cannot be rendered. Such errors are logged with slog, and
+// their text is not sent to the client.
+func OutputHandler(
+	fn func(*http.Request) (any, error),
+	supported ...Format,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		f, ct, err := NegotiateFormat(r.Header.Get("Accept"), supported...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+
+		out := f.Outputter()
+		if out == nil {
+			serverError(w, r, fmt.Errorf("format %s has no outputter", f))
+			return
+		}
+
+		val, err := fn(r)
+		if err != nil {
+			serverError(w, r, err)
+			return
+		}
+
+		res, err := OutputE(out, val, f)
+		if err != nil {
+			serverError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", ct)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(res + "\n"))
+	})
+}
+
+// serverError logs the error and responds with a generic 500 Internal
+// Server Error, so internal details do not leak to clients.
+func serverError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.Error("Cannot serve output",
+		"method", r.Method, "path", r.URL.Path, "error", err)
+	code := http.StatusInternalServerError
+	http.Error(w, http.StatusText(code), code)
+}
+
+// outputFormats returns registered formats that have a MIME type and an
+// Outputter. CompactJSON goes first, so it is used for wildcards and
+// empty Accept headers.
+func outputFormats() []Format {
+	fs := Formats()
+	if i := slices.Index(fs, CompactJSON); i > 0 {
+		fs = slices.Insert(slices.Delete(fs, i, i+1), 0, CompactJSON)
+	}
+
+	var res []Format
+	for _, f := range fs {
+		spec, _ := f.Spec()
+		if spec.MIMEType != "" && spec.NewOutputter != nil {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// parseAccept parses the Accept header into media ranges. Ranges with
+// invalid syntax are ignored.
+func parseAccept(accept string) []mediaRange {
+	accept = strings.TrimSpace(accept)
+	if accept == "" {
+		return []mediaRange{{typ: "*", subtype: "*", q: 1}}
+	}
+
+	var res []mediaRange
+	for part := range strings.SplitSeq(accept, ",") {
+		params := strings.Split(part, ";")
+		typ, subtype, ok := strings.Cut(strings.TrimSpace(params[0]), "/")
+		if !ok || typ == "" || subtype == "" {
+			continue
+		}
+
+		mr := mediaRange{
+			typ:     strings.ToLower(strings.TrimSpace(typ)),
+			subtype: strings.ToLower(strings.TrimSpace(subtype)),
+			q:       1,
+		}
+		for _, p := range params[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if !strings.EqualFold(strings.TrimSpace(k), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err == nil && q >= 0 && q <= 1 {
+				mr.q = q
+			}
+		}
+		res = append(res, mr)
+	}
+	return res
+}
+
+// acceptQuality returns the quality of a MIME type according to the
+// most specific media range that matches it.
+func acceptQuality(ranges []mediaRange, mime string) float64 {
+	typ, subtype, _ := strings.Cut(strings.ToLower(mime), "/")
+
+	var res float64
+	specificity := -1
+	for _, v := range ranges {
+		var s int
+		switch {
+		case v.typ == typ && v.subtype == subtype:
+			s = 2
+		case v.typ == typ && v.subtype == "*":
+			s = 1
+		case v.typ == "*" && v.subtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity || (s == specificity && v.q > res) {
+			res, specificity = v.q, s
+		}
+	}
+	return res
+}
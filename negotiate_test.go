@@ -0,0 +1,119 @@
+package gnfmt_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	assert := assert.New(t)
+	supported := []Format{CompactJSON, CSV, TSV}
+	tests := []struct {
+		msg    string
+		accept string
+		format Format
+		ct     string
+		errNil bool
+	}{
+		{"empty", "", CompactJSON, "application/json; charset=utf-8", true},
+		{"any", "*/*", CompactJSON, "application/json; charset=utf-8", true},
+		{"csv", "text/csv", CSV, "text/csv; charset=utf-8", true},
+		{"q", "application/json;q=0.5, text/csv;q=0.8", CSV,
+			"text/csv; charset=utf-8", true},
+		{"wildcard type", "text/*", CSV, "text/csv; charset=utf-8", true},
+		{"specific wins", "text/*;q=0.9, text/csv;q=0.1", TSV,
+			"text/tab-separated-values; charset=utf-8", true},
+		{"browser", "text/html,application/xhtml+xml,*/*;q=0.8",
+			CompactJSON, "application/json; charset=utf-8", true},
+		{"zero", "application/json;q=0, */*;q=0.1", CSV,
+			"text/csv; charset=utf-8", true},
+		{"none", "image/png", FormatNone, "", false},
+	}
+
+	for _, v := range tests {
+		f, ct, err := NegotiateFormat(v.accept, supported...)
+		assert.Equal(v.format, f, v.msg)
+		assert.Equal(v.ct, ct, v.msg)
+		assert.Equal(v.errNil, err == nil, v.msg)
+	}
+
+	f, _, err := NegotiateFormat("application/yaml")
+	assert.Nil(err)
+	assert.Equal(YAML, f)
+
+	// all registered formats prefer JSON for wildcards
+	for _, v := range []string{"", "*/*", "application/*"} {
+		f, ct, err := NegotiateFormat(v)
+		assert.Nil(err, v)
+		assert.Equal(CompactJSON, f, v)
+		assert.Equal("application/json; charset=utf-8", ct, v)
+	}
+}
+
+func TestOutputHandlerAllFormats(t *testing.T) {
+	assert := assert.New(t)
+	fn := func(*http.Request) (any, error) {
+		return map[string]int{"a": 1}, nil
+	}
+	h := OutputHandler(fn)
+
+	for _, v := range []string{"", "*/*"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", v)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(http.StatusOK, rec.Code, v)
+		assert.Equal("application/json; charset=utf-8",
+			rec.Header().Get("Content-Type"), v)
+		assert.Equal(`{"a":1}`+"\n", rec.Body.String(), v)
+	}
+}
+
+func TestOutputHandler(t *testing.T) {
+	assert := assert.New(t)
+	logger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(logger) })
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	fn := func(r *http.Request) (any, error) {
+		if r.URL.Path == "/fail" {
+			return nil, errors.New("fail")
+		}
+		return []version{{Version: "v1", Build: "today"}}, nil
+	}
+	h := OutputHandler(fn, CompactJSON, CSV)
+
+	tests := []struct {
+		msg, path, accept string
+		status            int
+		ct, body          string
+	}{
+		{"json", "/", "application/json", http.StatusOK,
+			"application/json; charset=utf-8",
+			`[{"Version":"v1","Build":"today"}]` + "\n"},
+		{"csv", "/", "text/csv, application/json;q=0.5", http.StatusOK,
+			"text/csv; charset=utf-8", "Version,Build\nv1,today\n"},
+		{"not acceptable", "/", "image/png", http.StatusNotAcceptable,
+			"text/plain; charset=utf-8", "no acceptable format\n"},
+		{"fail", "/fail", "", http.StatusInternalServerError,
+			"text/plain; charset=utf-8", "Internal Server Error\n"},
+	}
+
+	for _, v := range tests {
+		req := httptest.NewRequest(http.MethodGet, v.path, nil)
+		req.Header.Set("Accept", v.accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(v.status, rec.Code, v.msg)
+		assert.Equal(v.ct, rec.Header().Get("Content-Type"), v.msg)
+		assert.Equal(v.body, rec.Body.String(), v.msg)
+		assert.Equal("Accept", rec.Header().Get("Vary"), v.msg)
+	}
+}
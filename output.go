@@ -0,0 +1,44 @@
+package gnfmt
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when an outputter cannot render data
+	// in the requested format.
+	ErrUnsupportedFormat = errors.New("unsupported format")
+
+	// ErrEmptyOutput is returned by OutputE when an Outputter without
+	// error reporting returns an empty string.
+	ErrEmptyOutput = errors.New("empty output")
+)
+
+// OutputE creates an output of a record with the given Outputter. If the
+// Outputter implements OutputterE, its errors are returned as is.
+// Otherwise an empty result is reported as ErrEmptyOutput.
+func OutputE(o Outputter, record any, f Format) (string, error) {
+	if oe, ok := o.(OutputterE); ok {
+		return oe.OutputE(record, f)
+	}
+	res := o.Output(record, f)
+	if res == "" {
+		return "", ErrEmptyOutput
+	}
+	return res, nil
+}
+
+// unsupportedFormat creates an error for a format that an outputter is
+// not able to produce.
+func unsupportedFormat(f Format) error {
+	name := f.String()
+	if name == "" {
+		name = fmt.Sprintf("format %d", int(f))
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+}
+
+// errNoColumns is returned by tabular outputters when a record does not
+// have any columns.
+var errNoColumns = errors.New("record has no columns")
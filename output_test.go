@@ -0,0 +1,85 @@
+package gnfmt_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestOutputE(t *testing.T) {
+	assert := assert.New(t)
+	recs := []canonical{{Simple: "Bubo bubo", Full: "Bubo bubo"}}
+
+	tests := []struct {
+		msg    string
+		out    OutputterE
+		input  any
+		format Format
+		output string
+		err    error
+	}{
+		{"csv", GNcsv{}, recs, CSV,
+			"simple,canonicalFull\nBubo bubo,Bubo bubo", nil},
+		{"csv as json", GNcsv{}, recs, CompactJSON, "", ErrUnsupportedFormat},
+		{"json", GNjson{}, recs, CompactJSON,
+			`[{"simple":"Bubo bubo","full":"Bubo bubo"}]`, nil},
+		{"json as csv", GNjson{}, recs, CSV, "", ErrUnsupportedFormat},
+		{"json none", GNjson{}, recs, FormatNone, "", ErrUnsupportedFormat},
+		{"yaml as xml", GNyaml{}, recs, XML, "", ErrUnsupportedFormat},
+		{"xml as yaml", GNxml{}, recs, YAML, "", ErrUnsupportedFormat},
+		{"markdown as html", GNmarkdown{}, recs, HTML, "", ErrUnsupportedFormat},
+		{"table as csv", GNtable{}, recs, CSV, "", ErrUnsupportedFormat},
+		{"html as table", GNhtml{}, recs, Table, "", ErrUnsupportedFormat},
+	}
+
+	for _, v := range tests {
+		res, err := v.out.OutputE(v.input, v.format)
+		assert.Equal(v.output, res, v.msg)
+		if v.err == nil {
+			assert.Nil(err, v.msg)
+			continue
+		}
+		assert.ErrorIs(err, v.err, v.msg)
+		assert.Equal(v.output, v.out.Output(v.input, v.format), v.msg)
+	}
+}
+
+func TestOutputEErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := GNjson{}.OutputE(make(chan int), CompactJSON)
+	assert.NotNil(err)
+	assert.False(errors.Is(err, ErrUnsupportedFormat))
+
+	_, err = GNcsv{}.OutputE([]int{1, 2}, CSV)
+	assert.ErrorContains(err, "cannot tabulate")
+
+	_, err = GNtable{}.OutputE(struct{}{}, Table)
+	assert.ErrorContains(err, "no columns")
+
+	_, err = GNjson{}.OutputE(1, YAML)
+	assert.ErrorContains(err, "unsupported format: YAML")
+}
+
+func TestOutputEFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	res, err := OutputE(GNjson{}, []int{1}, CompactJSON)
+	assert.Nil(err)
+	assert.Equal("[1]", res)
+
+	_, err = OutputE(GNjson{}, []int{1}, CSV)
+	assert.ErrorIs(err, ErrUnsupportedFormat)
+
+	f, err := registerUpper()
+	assert.Nil(err)
+	res, err = OutputE(f.Outputter(), "abc", f)
+	assert.Nil(err)
+	assert.Equal("ABC", res)
+
+	_, err = OutputE(f.Outputter(), 1, f)
+	assert.ErrorIs(err, ErrEmptyOutput)
+}
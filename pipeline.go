@@ -0,0 +1,170 @@
+package gnfmt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// Pipeline encodes objects received from a channel concurrently and
+// writes the results to an io.Writer in the order of input. Objects are
+// converted either by the Encoder, or, if it is nil, by the Outputter
+// with the given Format.
+type Pipeline struct {
+	// Encoder converts objects into bytes. It must be safe for concurrent
+	// use, all encoders of this package are.
+	Encoder Encoder
+
+	// Outputter creates outputs of objects in the Format. It is used only
+	// if the Encoder is nil.
+	Outputter Outputter
+
+	// Format of the output for the Outputter.
+	Format Format
+
+	// Workers is the number of goroutines that encode objects. If it is
+	// less than 1, the number of CPUs is used.
+	Workers int
+
+	// Delimiter is written after every encoded object. If it is empty,
+	// a new line is used.
+	Delimiter string
+}
+
+// pipelineResult is an encoded object or an encoding error.
+type pipelineResult struct {
+	data []byte
+	err  error
+}
+
+// pipelineJob is an object to encode and a channel for its result.
+type pipelineJob struct {
+	input any
+	res   chan pipelineResult
+}
+
+// Run encodes all objects from the inputs channel and writes them to w
+// in the order they were received. At most a few results per worker are
+// kept in memory, when the writer is slow, reading from the inputs
+// channel is paused. Run stops when the inputs channel is closed, the
+// context is canceled, or encoding or writing fails. It returns the number
+// of written objects. The inputs channel is not closed by Run.
+func (p Pipeline) Run(
+	ctx context.Context,
+	inputs <-chan any,
+	w io.Writer,
+) (int, error) {
+	encode, err := p.encodeFunc()
+	if err != nil {
+		return 0, err
+	}
+
+	workers := p.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	delim := []byte(p.Delimiter)
+	if len(delim) == 0 {
+		delim = []byte("\n")
+	}
+
+	pctx, cancel := context.WithCancel(ctx)
+	jobs := make(chan pipelineJob)
+	// results are queued in the input order, the capacity of the queue
+	// limits the number of objects that are processed at once.
+	queue := make(chan chan pipelineResult, 2*workers)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		defer close(queue)
+		defer close(jobs)
+		dispatchJobs(pctx, inputs, jobs, queue)
+	})
+	for range workers {
+		wg.Go(func() {
+			for job := range jobs {
+				data, err := encode(job.input)
+				job.res <- pipelineResult{data: data, err: err}
+			}
+		})
+	}
+
+	var count int
+	for res := range queue {
+		if ctx.Err() != nil {
+			break
+		}
+		r := <-res
+		if r.err == nil {
+			_, r.err = w.Write(append(r.data, delim...))
+		}
+		if r.err != nil {
+			err = r.err
+			break
+		}
+		count++
+	}
+	cancel()
+	wg.Wait()
+
+	if err != nil {
+		return count, err
+	}
+	return count, ctx.Err()
+}
+
+// dispatchJobs sends objects from the inputs channel to workers, and
+// places channels for their results into the queue in the same order.
+func dispatchJobs(
+	ctx context.Context,
+	inputs <-chan any,
+	jobs chan<- pipelineJob,
+	queue chan<- chan pipelineResult,
+) {
+	for ctx.Err() == nil {
+		var input any
+		select {
+		case <-ctx.Done():
+			return
+		case v, ok := <-inputs:
+			if !ok {
+				return
+			}
+			input = v
+		}
+
+		res := make(chan pipelineResult, 1)
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- pipelineJob{input: input, res: res}:
+		}
+		// the job is taken by a worker, so its result will be ready
+		// eventually.
+		select {
+		case <-ctx.Done():
+			return
+		case queue <- res:
+		}
+	}
+}
+
+// encodeFunc returns a function that converts an object according to the
+// settings of the Pipeline.
+func (p Pipeline) encodeFunc() (func(any) ([]byte, error), error) {
+	if p.Encoder != nil {
+		return p.Encoder.Encode, nil
+	}
+	if p.Outputter == nil {
+		return nil, errors.New("pipeline needs an Encoder or an Outputter")
+	}
+	return func(input any) ([]byte, error) {
+		res, err := OutputE(p.Outputter, input, p.Format)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(res), nil
+	}, nil
+}
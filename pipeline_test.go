@@ -0,0 +1,170 @@
+package gnfmt_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+// slowEncoder encodes integers, smaller numbers take more time.
+type slowEncoder struct{}
+
+func (slowEncoder) Encode(input any) ([]byte, error) {
+	i := input.(int)
+	if i < 0 {
+		return nil, fmt.Errorf("negative number %d", i)
+	}
+	time.Sleep(time.Duration(10-i%10) * 100 * time.Microsecond)
+	return []byte(strconv.Itoa(i)), nil
+}
+
+func (slowEncoder) Decode([]byte, any) error { return nil }
+
+func sendInts(nums ...int) <-chan any {
+	ch := make(chan any)
+	go func() {
+		defer close(ch)
+		for _, v := range nums {
+			ch <- v
+		}
+	}()
+	return ch
+}
+
+func TestPipelineOrder(t *testing.T) {
+	assert := assert.New(t)
+	nums := make([]int, 500)
+	exp := make([]string, len(nums))
+	for i := range nums {
+		nums[i] = i
+		exp[i] = strconv.Itoa(i)
+	}
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		var buf bytes.Buffer
+		p := Pipeline{Encoder: slowEncoder{}, Workers: workers}
+		count, err := p.Run(context.Background(), sendInts(nums...), &buf)
+		assert.Nil(err)
+		assert.Equal(len(nums), count)
+		assert.Equal(strings.Join(exp, "\n")+"\n", buf.String())
+	}
+}
+
+func TestPipelineOutputter(t *testing.T) {
+	assert := assert.New(t)
+	vers := []version{
+		{Version: "v1.0.0", Build: "one"},
+		{Version: "v1.0.1", Build: "two"},
+	}
+	ch := make(chan any)
+	go func() {
+		defer close(ch)
+		for _, v := range vers {
+			ch <- v
+		}
+	}()
+
+	var buf bytes.Buffer
+	p := Pipeline{
+		Outputter: GNcsv{NoHeader: true},
+		Format:    CSV,
+		Workers:   2,
+		Delimiter: "\r\n",
+	}
+	count, err := p.Run(context.Background(), ch, &buf)
+	assert.Nil(err)
+	assert.Equal(2, count)
+	assert.Equal("v1.0.0,one\r\nv1.0.1,two\r\n", buf.String())
+
+	p = Pipeline{Outputter: GNcsv{}, Format: YAML}
+	_, err = p.Run(context.Background(), sendInts(1), &buf)
+	assert.ErrorIs(err, ErrUnsupportedFormat)
+
+	_, err = Pipeline{}.Run(context.Background(), sendInts(1), &buf)
+	assert.NotNil(err)
+}
+
+func TestPipelineEncoder(t *testing.T) {
+	assert := assert.New(t)
+	vers := []any{
+		version{Version: "v1.0.0", Build: "one"},
+		version{Version: "v1.0.1", Build: "two"},
+	}
+	ch := make(chan any, len(vers))
+	for _, v := range vers {
+		ch <- v
+	}
+	close(ch)
+
+	var buf bytes.Buffer
+	p := Pipeline{Encoder: GNjson{}, Workers: 4}
+	_, err := p.Run(context.Background(), ch, &buf)
+	assert.Nil(err)
+
+	dec := NewJSONLDecoder(&buf)
+	for _, v := range vers {
+		var ver version
+		assert.Nil(dec.Decode(&ver))
+		assert.Equal(v, ver)
+	}
+}
+
+type failWriter struct{ n int }
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("disk is full")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestPipelineErrors(t *testing.T) {
+	assert := assert.New(t)
+	p := Pipeline{Encoder: slowEncoder{}, Workers: 4}
+
+	var buf bytes.Buffer
+	count, err := p.Run(context.Background(), sendInts(0, 1, 2, -3, 4, 5), &buf)
+	assert.ErrorContains(err, "negative number -3")
+	assert.Equal(3, count)
+	assert.Equal("0\n1\n2\n", buf.String())
+
+	count, err = p.Run(
+		context.Background(), sendInts(0, 1, 2, 3), &failWriter{n: 2},
+	)
+	assert.ErrorContains(err, "disk is full")
+	assert.Equal(2, count)
+}
+
+func TestPipelineCancel(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan any)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- i:
+			}
+			if i == 10 {
+				cancel()
+			}
+		}
+	}()
+
+	var buf bytes.Buffer
+	p := Pipeline{Encoder: slowEncoder{}, Workers: 2}
+	count, err := p.Run(ctx, ch, &buf)
+	assert.ErrorIs(err, context.Canceled)
+	assert.LessOrEqual(count, 11)
+}
@@ -0,0 +1,334 @@
+package gnfmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// diffMaxEdits limits the number of edits the diff algorithm looks for.
+// Saved states of the algorithm grow with the square of this number, so
+// it is kept small. If two texts differ more, they are shown as replaced.
+const diffMaxEdits = 1000
+
+// diffOp is one line of a line diff. Kind is ' ' for equal lines, '-'
+// for lines of the first text, and '+' for lines of the second text. A and
+// B are the numbers of lines of the texts that precede the line.
+type diffOp struct {
+	kind byte
+	a, b int
+}
+
+// PprDiff pretty prints two objects the same way as Ppr and returns a
+// unified diff of the results. Headers of hunks contain JSON paths of
+// changed values, for example `$.names[2].canonical`. If objects have
+// the same representation, it returns an empty string. With OptPprColor
+// removed lines are red and added lines are green.
+func PprDiff(a, b any, opts ...PprOption) string {
+	cfg := newPprConfig(opts)
+	txtA, err := pprint(a, opts)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	txtB, err := pprint(b, opts)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	if txtA == txtB {
+		return ""
+	}
+
+	linesA := strings.Split(txtA, "\n")
+	linesB := strings.Split(txtB, "\n")
+	pathsA := jsonLinePaths(linesA)
+	pathsB := jsonLinePaths(linesB)
+	ops := diffLines(linesA, linesB)
+
+	paint := func(c interface{ Sprint(...any) string }, s string) string {
+		if cfg.color {
+			return c.Sprint(s)
+		}
+		return s
+	}
+
+	var res strings.Builder
+	res.WriteString(paint(diffDelColor, "--- a") + "\n")
+	res.WriteString(paint(diffAddColor, "+++ b") + "\n")
+	for _, h := range diffHunks(ops) {
+		hunk := ops[h[0]:h[1]]
+		var countA, countB int
+		var paths []string
+		for _, op := range hunk {
+			switch op.kind {
+			case ' ':
+				countA++
+				countB++
+			case '-':
+				countA++
+				paths = append(paths, pathsA[op.a])
+			case '+':
+				countB++
+				paths = append(paths, pathsB[op.b])
+			}
+		}
+		header := fmt.Sprintf(
+			"@@ -%s +%s @@ %s",
+			hunkRange(hunk[0].a, countA),
+			hunkRange(hunk[0].b, countB),
+			strings.Join(topPaths(paths), ", "),
+		)
+		res.WriteString(paint(diffHunkColor, header) + "\n")
+
+		for _, op := range hunk {
+			switch op.kind {
+			case ' ':
+				res.WriteString(" " + linesA[op.a] + "\n")
+			case '-':
+				res.WriteString(paint(diffDelColor, "-"+linesA[op.a]) + "\n")
+			case '+':
+				res.WriteString(paint(diffAddColor, "+"+linesB[op.b]) + "\n")
+			}
+		}
+	}
+	return strings.TrimSuffix(res.String(), "\n")
+}
+
+// hunkRange formats the start and the length of a hunk for its header.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// diffHunks groups changes with their context into hunks. It returns
+// ranges of indices of ops.
+func diffHunks(ops []diffOp) [][2]int {
+	var res [][2]int
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start := max(0, i-diffContext)
+		end := min(len(ops), i+diffContext+1)
+		if l := len(res); l > 0 && start <= res[l-1][1] {
+			res[l-1][1] = end
+			continue
+		}
+		res = append(res, [2]int{start, end})
+	}
+	return res
+}
+
+// topPaths removes duplicate paths and paths that are inside of other
+// paths in the list.
+func topPaths(paths []string) []string {
+	var res []string
+	for _, p := range paths {
+		if slices.ContainsFunc(res, func(v string) bool {
+			return v == p || isSubPath(p, v)
+		}) {
+			continue
+		}
+		res = slices.DeleteFunc(res, func(v string) bool {
+			return isSubPath(v, p)
+		})
+		res = append(res, p)
+	}
+	return res
+}
+
+// isSubPath checks if a JSON path points inside of a parent path.
+func isSubPath(path, parent string) bool {
+	rest, ok := strings.CutPrefix(path, parent)
+	return ok && rest != "" && (rest[0] == '.' || rest[0] == '[')
+}
+
+// jsonLinePaths finds JSON paths of values that start or end on every
+// line of indented JSON.
+func jsonLinePaths(lines []string) []string {
+	type frame struct {
+		path    string
+		isArray bool
+		index   int
+	}
+	var stack []frame
+
+	res := make([]string, len(lines))
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "}") || strings.HasPrefix(line, "]") {
+			if len(stack) == 0 {
+				res[i] = "$"
+				continue
+			}
+			res[i] = stack[len(stack)-1].path
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		path := "$"
+		if l := len(stack); l > 0 {
+			top := &stack[l-1]
+			switch {
+			case top.isArray && strings.HasPrefix(line, "…"):
+				path = top.path
+			case top.isArray:
+				path = top.path + "[" + strconv.Itoa(top.index) + "]"
+				top.index++
+			default:
+				path = top.path + keyPath(line)
+			}
+		}
+		res[i] = path
+
+		if strings.HasSuffix(line, "{") || strings.HasSuffix(line, "[") {
+			stack = append(stack, frame{
+				path:    path,
+				isArray: strings.HasSuffix(line, "["),
+			})
+		}
+	}
+	return res
+}
+
+// keyPath converts the key of an object member line into a part of
+// a JSON path.
+func keyPath(line string) string {
+	var key string
+	end := jsonStringEnd(line, 0)
+	if err := json.Unmarshal([]byte(line[:end]), &key); err != nil {
+		return ""
+	}
+	if isIdentifier(key) {
+		return "." + key
+	}
+	return "[" + jsonString(key) + "]"
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// diffLines finds the shortest edit script that converts lines of a to
+// lines of b with Myers' algorithm.
+func diffLines(a, b []string) []diffOp {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	res := make([]diffOp, 0, len(a)+len(b))
+	for i := range prefix {
+		res = append(res, diffOp{kind: ' ', a: i, b: i})
+	}
+	middle := myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for _, op := range middle {
+		op.a += prefix
+		op.b += prefix
+		res = append(res, op)
+	}
+	for i := suffix; i > 0; i-- {
+		res = append(res, diffOp{kind: ' ', a: len(a) - i, b: len(b) - i})
+	}
+	return res
+}
+
+// myers returns an edit script for two lists of lines.
+func myers(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+	maxD := min(n+m, diffMaxEdits)
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, slices.Clone(v[offset-d-1:offset+d+2]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return myersBacktrack(trace, n, m)
+			}
+		}
+	}
+
+	// too many differences, replace everything
+	res := make([]diffOp, 0, n+m)
+	for i := range n {
+		res = append(res, diffOp{kind: '-', a: i, b: 0})
+	}
+	for i := range m {
+		res = append(res, diffOp{kind: '+', a: n, b: i})
+	}
+	return res
+}
+
+// myersBacktrack restores the edit script from saved states of Myers'
+// algorithm. A state for the step d keeps values for diagonals from
+// -d-1 to d+1.
+func myersBacktrack(trace [][]int, n, m int) []diffOp {
+	var res []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			res = append(res, diffOp{kind: ' ', a: x, b: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			res = append(res, diffOp{kind: '+', a: x, b: prevY})
+		} else {
+			res = append(res, diffOp{kind: '-', a: prevX, b: y})
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(res)
+	return res
+}
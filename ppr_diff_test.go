@@ -0,0 +1,113 @@
+package gnfmt_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gnames/gnfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+type diffName struct {
+	Name    string   `json:"name"`
+	Authors []string `json:"authors"`
+	Year    int      `json:"year"`
+}
+
+type diffResult struct {
+	Input string     `json:"input"`
+	Names []diffName `json:"names"`
+	Meta  struct {
+		Total int `json:"total"`
+	} `json:"meta"`
+}
+
+func TestPprDiff(t *testing.T) {
+	assert := assert.New(t)
+	a := diffResult{
+		Input: "Bubo bubo",
+		Names: []diffName{
+			{Name: "Bubo bubo", Authors: []string{"Linnaeus"}, Year: 1758},
+			{Name: "Bubo", Authors: []string{"Duméril"}, Year: 1805},
+		},
+	}
+	b := a
+	b.Names = []diffName{
+		a.Names[0],
+		{Name: "Bubo", Authors: []string{"Duméril", "Cuvier"}, Year: 1806},
+	}
+
+	assert.Equal("", gnfmt.PprDiff(a, a))
+
+	res := gnfmt.PprDiff(a, b)
+	assert.Equal(`--- a
++++ b
+@@ -11,9 +11,10 @@ $.names[1].authors[0], $.names[1].authors[1], $.names[1].year
+     {
+       "name": "Bubo",
+       "authors": [
+-        "Duméril"
++        "Duméril",
++        "Cuvier"
+       ],
+-      "year": 1805
++      "year": 1806
+     }
+   ],
+   "meta": {`, res)
+}
+
+func TestPprDiffPaths(t *testing.T) {
+	assert := assert.New(t)
+	a := map[string]any{
+		"a b":   1,
+		"list":  []int{1, 2, 3},
+		"inner": map[string]any{"x": "y"},
+	}
+	b := map[string]any{
+		"a b":   2,
+		"list":  []int{1, 2, 3},
+		"inner": map[string]any{"x": "y", "z": map[string]int{"q": 1}},
+	}
+
+	res := gnfmt.PprDiff(a, b)
+	lines := strings.Split(res, "\n")
+	assert.Equal(`@@ -1,7 +1,10 @@ $["a b"], $.inner.x, $.inner.z`, lines[2])
+	assert.Contains(res, "\n-  \"a b\": 1,\n+  \"a b\": 2,\n")
+
+	res = gnfmt.PprDiff([]int{1}, []int{})
+	assert.Equal("--- a\n+++ b\n@@ -1,3 +1,1 @@ $\n-[\n-  1\n-]\n+[]", res)
+}
+
+func TestPprDiffOptions(t *testing.T) {
+	assert := assert.New(t)
+	a := make([]int, 100)
+	b := make([]int, 100)
+	b[50] = 1
+
+	res := gnfmt.PprDiff(a, b, gnfmt.OptPprMaxElements(10))
+	assert.Equal("", res)
+
+	res = gnfmt.PprDiff(a, b)
+	assert.Contains(res, "@@ -49,7 +49,7 @@ $[50]\n")
+
+	res = gnfmt.PprDiff(a, b, gnfmt.OptPprColor(true))
+	assert.Contains(res, "\x1b[31m-  0,\x1b[0m\n\x1b[32m+  1,\x1b[0m")
+
+	res = gnfmt.PprDiff(make(chan int), a)
+	assert.True(strings.HasPrefix(res, "Error: "))
+}
+
+func TestPprDiffLarge(t *testing.T) {
+	assert := assert.New(t)
+	a := make([]string, 5000)
+	b := make([]string, 5000)
+	for i := range a {
+		a[i] = fmt.Sprintf("a%d", i)
+		b[i] = fmt.Sprintf("b%d", i)
+	}
+	res := gnfmt.PprDiff(a, b)
+	assert.Equal(5000, strings.Count(res, "\n-  \"a"))
+	assert.Equal(5000, strings.Count(res, "\n+  \"b"))
+}
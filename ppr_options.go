@@ -0,0 +1,613 @@
+package gnfmt
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// RedactedValue replaces values of fields tagged with `gnfmt:"secret"`
+// when redaction is enabled by OptPprRedact.
+const RedactedValue = "[redacted]"
+
+// pprMaxNesting protects pretty print from cycles in data.
+const pprMaxNesting = 1000
+
+// pprConfig contains settings of pretty print.
+type pprConfig struct {
+	maxDepth     int
+	maxElements  int
+	maxStringLen int
+	sortKeys     bool
+	redact       bool
+	color        bool
+}
+
+// PprOption is a function that modifies settings of pretty print.
+type PprOption func(*pprConfig)
+
+// OptPprMaxDepth sets the maximum nesting depth of shown objects and
+// arrays. The top-level value has the depth 1, deeper objects and arrays
+// are collapsed to `{…N more}` and `[…N more]`. Zero means no limit.
+func OptPprMaxDepth(n int) PprOption {
+	return func(cfg *pprConfig) {
+		cfg.maxDepth = max(n, 0)
+	}
+}
+
+// OptPprMaxElements sets the maximum number of shown elements of arrays
+// and slices. The rest of the elements are replaced by the `…N more`
+// marker. Zero means no limit.
+func OptPprMaxElements(n int) PprOption {
+	return func(cfg *pprConfig) {
+		cfg.maxElements = max(n, 0)
+	}
+}
+
+// OptPprMaxStringLen sets the maximum number of shown characters of
+// strings. Longer strings are cut and end with the `…N more` marker.
+// Zero means no limit.
+func OptPprMaxStringLen(n int) PprOption {
+	return func(cfg *pprConfig) {
+		cfg.maxStringLen = max(n, 0)
+	}
+}
+
+// OptPprSortKeys sorts keys of all objects, including fields of structs.
+// Keys of maps are always sorted.
+func OptPprSortKeys(b bool) PprOption {
+	return func(cfg *pprConfig) {
+		cfg.sortKeys = b
+	}
+}
+
+// OptPprRedact replaces values of struct fields tagged with
+// `gnfmt:"secret"` with RedactedValue.
+func OptPprRedact(b bool) PprOption {
+	return func(cfg *pprConfig) {
+		cfg.redact = b
+	}
+}
+
+// OptPprColor adds colors to the output of Ppr, PprColor, PprTo and
+// PprDiff, even if the output is not a terminal.
+func OptPprColor(b bool) PprOption {
+	return func(cfg *pprConfig) {
+		cfg.color = b
+	}
+}
+
+// newPprConfig creates settings of pretty print from options.
+func newPprConfig(opts []PprOption) pprConfig {
+	var res pprConfig
+	for _, opt := range opts {
+		opt(&res)
+	}
+	return res
+}
+
+// pprint creates indented JSON representation of an object. Without
+// options the result is the same as from json.MarshalIndent.
+func pprint(obj any, opts []PprOption) (string, error) {
+	if len(opts) == 0 {
+		res, err := json.MarshalIndent(obj, "", "  ")
+		return string(res), err
+	}
+
+	cfg := newPprConfig(opts)
+	n, err := cfg.node(reflect.ValueOf(obj), 0)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	cfg.render(&b, n, 1)
+	return b.String(), nil
+}
+
+type pprKind int
+
+const (
+	pprScalar pprKind = iota
+	pprString
+	pprObject
+	pprArray
+)
+
+// pprNode is a part of a JSON document. Scalars keep their JSON text,
+// strings keep their value, objects and arrays keep their children.
+// Redacted strings are never truncated.
+type pprNode struct {
+	kind     pprKind
+	text     string
+	keys     []string
+	items    []pprNode
+	redacted bool
+}
+
+var (
+	jsonMarshalerType  = reflect.TypeFor[json.Marshaler]()
+	jsonNumberType     = reflect.TypeFor[json.Number]()
+	pprRedactedNode    = pprNode{kind: pprString, text: RedactedValue, redacted: true}
+	pprNullNode        = pprNode{kind: pprScalar, text: "null"}
+	errPprNestingDepth = errors.New("pretty print: data are nested too deep")
+)
+
+// node converts a value into a tree of nodes following the rules of
+// encoding/json.
+func (cfg pprConfig) node(v reflect.Value, nesting int) (pprNode, error) {
+	if nesting > pprMaxNesting {
+		return pprNode{}, errPprNestingDepth
+	}
+	if !v.IsValid() {
+		return pprNullNode, nil
+	}
+	if isJSONMarshaler(v) {
+		return marshalNode(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return pprNullNode, nil
+		}
+		return cfg.node(v.Elem(), nesting+1)
+	case reflect.String:
+		if v.Type() == jsonNumberType {
+			return marshalNode(v)
+		}
+		return pprNode{kind: pprString, text: v.String()}, nil
+	case reflect.Struct:
+		return cfg.structNode(v, nesting)
+	case reflect.Map:
+		return cfg.mapNode(v, nesting)
+	case reflect.Slice:
+		if v.IsNil() {
+			return pprNullNode, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return marshalNode(v)
+		}
+		return cfg.arrayNode(v, nesting)
+	case reflect.Array:
+		return cfg.arrayNode(v, nesting)
+	default:
+		return marshalNode(v)
+	}
+}
+
+func (cfg pprConfig) structNode(v reflect.Value, nesting int) (pprNode, error) {
+	res := pprNode{kind: pprObject}
+	for _, f := range pprFields(v.Type()) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			// embedded through a nil pointer
+			continue
+		}
+
+		if slices.Contains(f.opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		if slices.Contains(f.opts, "omitzero") && fv.IsZero() {
+			continue
+		}
+
+		var n pprNode
+		var err error
+		switch {
+		case cfg.redact && isSecretField(f.field):
+			n = pprRedactedNode
+		case slices.Contains(f.opts, "string"):
+			n, err = cfg.node(fv, nesting+1)
+			n = quotedNode(n)
+		default:
+			n, err = cfg.node(fv, nesting+1)
+		}
+		if err != nil {
+			return res, err
+		}
+		res.keys = append(res.keys, f.name)
+		res.items = append(res.items, n)
+	}
+	return res, nil
+}
+
+// pprField is a field of a struct that is present in its JSON. The
+// index is a path of field indices through embedded structs.
+type pprField struct {
+	name   string
+	tagged bool
+	index  []int
+	opts   []string
+	field  reflect.StructField
+}
+
+// pprFieldsCache keeps fields of struct types.
+var pprFieldsCache sync.Map
+
+// pprFields returns fields of a struct type that encoding/json would
+// encode, in the same order. Fields of embedded structs are promoted, and
+// conflicts of names are resolved by the rules of encoding/json: the
+// least nested field wins, then the tagged one, otherwise all of them
+// are omitted.
+func pprFields(t reflect.Type) []pprField {
+	if res, ok := pprFieldsCache.Load(t); ok {
+		return res.([]pprField)
+	}
+
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+	var current []queued
+	next := []queued{{typ: t}}
+	var count, nextCount map[reflect.Type]int
+	visited := make(map[reflect.Type]bool)
+
+	var fields []pprField
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, make(map[reflect.Type]int)
+
+		for _, q := range current {
+			if visited[q.typ] {
+				continue
+			}
+			visited[q.typ] = true
+
+			for i := range q.typ.NumField() {
+				sf := q.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					// unexported embedded structs may have exported fields
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, tagOpts, _ := strings.Cut(tag, ",")
+				index := append(slices.Clone(q.index), i)
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					f := pprField{
+						name:   name,
+						tagged: name != "",
+						index:  index,
+						opts:   strings.Split(tagOpts, ","),
+						field:  sf,
+					}
+					if name == "" {
+						f.name = sf.Name
+					}
+					fields = append(fields, f)
+					if count[q.typ] > 1 {
+						// the same type is embedded several times on this
+						// level, the duplicate annihilates the field.
+						fields = append(fields, f)
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, queued{typ: ft, index: index})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(fields, func(a, b pprField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.index) - len(b.index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	var res []pprField
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		// fields are sorted, so the first one is the dominant candidate
+		group := fields[i:j]
+		if len(group) == 1 || len(group[0].index) < len(group[1].index) ||
+			group[0].tagged != group[1].tagged {
+			res = append(res, group[0])
+		}
+		i = j
+	}
+	slices.SortFunc(res, func(a, b pprField) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	act, _ := pprFieldsCache.LoadOrStore(t, res)
+	return act.([]pprField)
+}
+
+func (cfg pprConfig) mapNode(v reflect.Value, nesting int) (pprNode, error) {
+	if v.IsNil() {
+		return pprNullNode, nil
+	}
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return pprNode{}, err
+		}
+		entries = append(entries, entry{key: key, val: iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	res := pprNode{kind: pprObject}
+	for _, e := range entries {
+		n, err := cfg.node(e.val, nesting+1)
+		if err != nil {
+			return res, err
+		}
+		res.keys = append(res.keys, e.key)
+		res.items = append(res.items, n)
+	}
+	return res, nil
+}
+
+func (cfg pprConfig) arrayNode(v reflect.Value, nesting int) (pprNode, error) {
+	res := pprNode{kind: pprArray, items: make([]pprNode, 0, v.Len())}
+	for i := range v.Len() {
+		n, err := cfg.node(v.Index(i), nesting+1)
+		if err != nil {
+			return res, err
+		}
+		res.items = append(res.items, n)
+	}
+	return res, nil
+}
+
+// render writes a node as indented JSON, applying truncation limits.
+func (cfg pprConfig) render(b *strings.Builder, n pprNode, depth int) {
+	switch n.kind {
+	case pprScalar:
+		b.WriteString(n.text)
+		return
+	case pprString:
+		s := n.text
+		if cfg.maxStringLen > 0 && !n.redacted {
+			if l := utf8.RuneCountInString(s); l > cfg.maxStringLen {
+				var cut int
+				for range cfg.maxStringLen {
+					_, size := utf8.DecodeRuneInString(s[cut:])
+					cut += size
+				}
+				s = s[:cut] + moreMarker(l-cfg.maxStringLen)
+			}
+		}
+		b.WriteString(jsonString(s))
+		return
+	}
+
+	open, closing := "{", "}"
+	if n.kind == pprArray {
+		open, closing = "[", "]"
+	}
+	if len(n.items) == 0 {
+		b.WriteString(open + closing)
+		return
+	}
+	if cfg.maxDepth > 0 && depth > cfg.maxDepth {
+		b.WriteString(open + moreMarker(len(n.items)) + closing)
+		return
+	}
+
+	order := make([]int, len(n.items))
+	for i := range order {
+		order[i] = i
+	}
+	if n.kind == pprObject && cfg.sortKeys {
+		slices.SortStableFunc(order, func(a, b int) int {
+			return strings.Compare(n.keys[a], n.keys[b])
+		})
+	}
+	var more int
+	if n.kind == pprArray && cfg.maxElements > 0 && len(order) > cfg.maxElements {
+		more = len(order) - cfg.maxElements
+		order = order[:cfg.maxElements]
+	}
+
+	indent := strings.Repeat("  ", depth)
+	b.WriteString(open)
+	for i, idx := range order {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n" + indent)
+		if n.kind == pprObject {
+			b.WriteString(jsonString(n.keys[idx]) + ": ")
+		}
+		cfg.render(b, n.items[idx], depth+1)
+	}
+	if more > 0 {
+		b.WriteString(",\n" + indent + moreMarker(more))
+	}
+	b.WriteString("\n" + strings.Repeat("  ", depth-1) + closing)
+}
+
+// moreMarker shows how many elements were omitted.
+func moreMarker(n int) string {
+	return "…" + strconv.Itoa(n) + " more"
+}
+
+// isJSONMarshaler checks if a value defines its own JSON or text
+// representation.
+func isJSONMarshaler(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return false
+	}
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return v.CanInterface()
+	}
+	if v.CanAddr() {
+		pt := reflect.PointerTo(t)
+		if pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+			return v.Addr().CanInterface()
+		}
+	}
+	return false
+}
+
+// marshalNode converts a value into JSON with encoding/json and parses
+// the result into a node.
+func marshalNode(v reflect.Value) (pprNode, error) {
+	var val any
+	switch {
+	case v.CanAddr() && v.Addr().CanInterface():
+		val = v.Addr().Interface()
+	case v.CanInterface():
+		val = v.Interface()
+	default:
+		return pprNullNode, nil
+	}
+	res, err := json.Marshal(val)
+	if err != nil {
+		return pprNode{}, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(res))
+	dec.UseNumber()
+	return parseNode(dec)
+}
+
+// parseNode reads a JSON value from a decoder preserving the order of
+// keys in objects.
+func parseNode(dec *json.Decoder) (pprNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return pprNode{}, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		res := pprNode{kind: pprArray}
+		if t == '{' {
+			res.kind = pprObject
+		}
+		for dec.More() {
+			if res.kind == pprObject {
+				key, err := dec.Token()
+				if err != nil {
+					return res, err
+				}
+				res.keys = append(res.keys, key.(string))
+			}
+			n, err := parseNode(dec)
+			if err != nil {
+				return res, err
+			}
+			res.items = append(res.items, n)
+		}
+		// closing delimiter
+		_, err = dec.Token()
+		return res, err
+	case string:
+		return pprNode{kind: pprString, text: t}, nil
+	case json.Number:
+		return pprNode{kind: pprScalar, text: t.String()}, nil
+	case bool:
+		return pprNode{kind: pprScalar, text: strconv.FormatBool(t)}, nil
+	default:
+		return pprNullNode, nil
+	}
+}
+
+// quotedNode converts a scalar node into a string the way the "string"
+// option of encoding/json does.
+func quotedNode(n pprNode) pprNode {
+	switch {
+	case n.kind == pprString:
+		return pprNode{kind: pprString, text: jsonString(n.text)}
+	case n.kind == pprScalar && n.text != "null":
+		return pprNode{kind: pprString, text: n.text}
+	}
+	return n
+}
+
+// mapKey converts a key of a map into a string the way encoding/json
+// does.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		res, err := tm.MarshalText()
+		return string(res), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("pretty print: unsupported map key type %s", k.Type())
+}
+
+// isSecretField checks if a field has `gnfmt:"secret"` tag.
+func isSecretField(f reflect.StructField) bool {
+	tag, ok := f.Tag.Lookup("gnfmt")
+	return ok && slices.Contains(strings.Split(tag, ","), "secret")
+}
+
+// isEmptyValue follows the definition of empty values for the omitempty
+// option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16,
+		reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// jsonString converts a string into JSON with the same escaping as
+// encoding/json.
+func jsonString(s string) string {
+	res, _ := json.Marshal(s)
+	return string(res)
+}
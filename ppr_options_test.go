@@ -0,0 +1,202 @@
+package gnfmt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnames/gnfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+type pprBase struct {
+	ID   int `json:"id"`
+	note string
+}
+
+type pprLevel int
+
+func (l pprLevel) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+type pprRecord struct {
+	pprBase
+	Name     string            `json:"name"`
+	Empty    string            `json:"empty,omitempty"`
+	Skip     string            `json:"-"`
+	Count    int64             `json:"count,string"`
+	Ratio    float64           `json:"ratio"`
+	Date     time.Time         `json:"date"`
+	Level    pprLevel          `json:"level"`
+	Levels   map[pprLevel]int  `json:"levels"`
+	ByNum    map[int]string    `json:"byNum"`
+	Raw      []byte            `json:"raw"`
+	Parent   *pprRecord        `json:"parent"`
+	Words    []string          `json:"words"`
+	Extra    any               `json:"extra"`
+	Password string            `json:"password" gnfmt:"secret"`
+	Meta     map[string]string `json:"meta,omitempty"`
+}
+
+func pprSample() pprRecord {
+	return pprRecord{
+		pprBase: pprBase{ID: 7, note: "hidden"},
+		Name:    "Bubo <bubo> & Co",
+		Skip:    "skip",
+		Count:   12,
+		Ratio:   0.1 + 0.2,
+		Date:    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Level:   3,
+		Levels:  map[pprLevel]int{2: 2, 1: 1},
+		ByNum:   map[int]string{10: "ten", 9: "nine"},
+		Raw:     []byte("raw"),
+		Parent:  &pprRecord{Name: "Bubo"},
+		Words:   []string{"one", "two", "three", "four"},
+		Extra: map[string]any{
+			"list": []any{1.5, true, nil},
+		},
+		Password: "qwerty",
+	}
+}
+
+func TestPprOptionsSameLayout(t *testing.T) {
+	assert := assert.New(t)
+	rec := pprSample()
+	exp := gnfmt.Ppr(rec)
+	assert.Equal(exp, gnfmt.Ppr(rec, gnfmt.OptPprSortKeys(false)))
+	assert.Equal(exp, gnfmt.Ppr(&rec, gnfmt.OptPprMaxDepth(0)))
+	for _, v := range []any{nil, 1, "a", []int{}, map[string]int{}, []int(nil)} {
+		assert.Equal(gnfmt.Ppr(v), gnfmt.Ppr(v, gnfmt.OptPprMaxElements(0)))
+	}
+}
+
+type pprInner struct {
+	A    string
+	B    int
+	Deep string `json:"deep"`
+}
+
+type pprTagged struct {
+	B string `json:"B"`
+	C string
+}
+
+type pprOther struct {
+	C string
+}
+
+type pprOuter struct {
+	A int
+	pprInner
+	*pprTagged
+	pprOther
+	Num  json.Number
+	Nums []any
+}
+
+func TestPprOptionsEmbedded(t *testing.T) {
+	assert := assert.New(t)
+	o := pprOuter{
+		A:         1,
+		pprInner:  pprInner{A: "x", B: 2, Deep: "d"},
+		pprTagged: &pprTagged{B: "tagged", C: "conflict"},
+		pprOther:  pprOther{C: "conflict"},
+		Num:       "12",
+		Nums:      []any{json.Number("1.5e3")},
+	}
+	exp := gnfmt.Ppr(o)
+	assert.Equal(exp, gnfmt.Ppr(o, gnfmt.OptPprSortKeys(false)))
+	assert.Contains(exp, `"Num": 12,`)
+	assert.Contains(exp, `"B": "tagged"`)
+	assert.NotContains(exp, `"C"`)
+	assert.Equal(1, strings.Count(exp, `"A"`))
+
+	o.pprTagged = nil
+	exp = gnfmt.Ppr(o)
+	assert.Equal(exp, gnfmt.Ppr(o, gnfmt.OptPprSortKeys(false)))
+}
+
+func TestPprOptions(t *testing.T) {
+	assert := assert.New(t)
+	obj := map[string]any{
+		"words": []string{"one", "two", "three", "four"},
+		"text":  "Ελληνικά κείμενο",
+		"nested": map[string]any{
+			"list":  []int{1, 2},
+			"empty": []int{},
+			"inner": map[string]int{"a": 1},
+		},
+	}
+
+	tests := []struct {
+		msg  string
+		opts []gnfmt.PprOption
+		res  string
+	}{
+		{"max elements", []gnfmt.PprOption{gnfmt.OptPprMaxElements(2)},
+			`"words": [
+    "one",
+    "two",
+    …2 more
+  ]`},
+		{"max string", []gnfmt.PprOption{gnfmt.OptPprMaxStringLen(4)},
+			`"text": "Ελλη…12 more"`},
+		{"depth 2", []gnfmt.PprOption{gnfmt.OptPprMaxDepth(2)},
+			`"nested": {
+    "empty": [],
+    "inner": {…1 more},
+    "list": […2 more]
+  }`},
+		{"depth 1", []gnfmt.PprOption{gnfmt.OptPprMaxDepth(1)},
+			`"nested": {…3 more}`},
+	}
+
+	for _, v := range tests {
+		res := gnfmt.Ppr(obj, v.opts...)
+		assert.Contains(res, v.res, v.msg)
+	}
+}
+
+func TestPprSortRedact(t *testing.T) {
+	assert := assert.New(t)
+	type user struct {
+		Name  string
+		Token string `gnfmt:"secret"`
+		Age   int
+	}
+	u := user{Name: "Alice", Token: "abc", Age: 30}
+
+	res := gnfmt.Ppr(u, gnfmt.OptPprSortKeys(true))
+	assert.Equal(
+		"{\n  \"Age\": 30,\n  \"Name\": \"Alice\",\n  \"Token\": \"abc\"\n}",
+		res,
+	)
+
+	res = gnfmt.Ppr(u, gnfmt.OptPprRedact(true))
+	assert.Equal(
+		"{\n  \"Name\": \"Alice\",\n  \"Token\": \"[redacted]\",\n  \"Age\": 30\n}",
+		res,
+	)
+	assert.NotContains(gnfmt.Ppr(pprSample(), gnfmt.OptPprRedact(true)), "qwerty")
+	assert.NotContains(gnfmt.PprColor(u, gnfmt.OptPprRedact(true)), "abc")
+
+	res = gnfmt.Ppr(u, gnfmt.OptPprRedact(true), gnfmt.OptPprMaxStringLen(3))
+	assert.Contains(res, `"Token": "[redacted]"`)
+	assert.Contains(res, `"Name": "Ali…2 more"`)
+}
+
+func TestPprOptionsErrors(t *testing.T) {
+	assert := assert.New(t)
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+	res := gnfmt.Ppr(n, gnfmt.OptPprSortKeys(true))
+	assert.True(strings.HasPrefix(res, "Error: "))
+
+	res = gnfmt.Ppr(map[bool]int{true: 1}, gnfmt.OptPprSortKeys(true))
+	assert.True(strings.HasPrefix(res, "Error: "))
+}
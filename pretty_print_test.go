@@ -1,7 +1,9 @@
 package gnfmt_test
 
 import (
+	"bytes"
 	"math"
+	"regexp"
 	"testing"
 
 	"github.com/gnames/gnfmt"
@@ -28,3 +30,51 @@ func TestPpr(t *testing.T) {
 	res := gnfmt.Ppr(o)
 	assert.Equal("{\n  \"A\": \"one\",\n  \"B\": 345,\n  \"C\": [\n    1,\n    44\n  ],\n  \"D\": [\n    \"one\",\n    \"two\"\n  ],\n  \"E\": 3.1415927\n}", res)
 }
+
+func TestPprColor(t *testing.T) {
+	assert := assert.New(t)
+	o := map[string]any{
+		"key":  "a \"quoted\": value",
+		"num":  -1.5e-7,
+		"ok":   true,
+		"none": nil,
+		"list": []any{1, "two", false},
+	}
+	res := gnfmt.Ppr(o, gnfmt.OptPprColor(true))
+
+	ansi := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	assert.Equal(gnfmt.Ppr(o), ansi.ReplaceAllString(res, ""))
+	assert.Contains(res, "\x1b[34;1m\"key\"\x1b[0;22m: ")
+	assert.Contains(res, "\x1b[32m\"a \\\"quoted\\\": value\"\x1b[0m")
+	assert.Contains(res, "\x1b[36m-1.5e-7\x1b[0m")
+	assert.Contains(res, "\x1b[36m1\x1b[0m,")
+	assert.Contains(res, "\x1b[33mtrue\x1b[0m")
+	assert.Contains(res, "\x1b[33mfalse\x1b[0m")
+	assert.Contains(res, "\x1b[35mnull\x1b[0m")
+	assert.Contains(res, "\x1b[32m\"two\"\x1b[0m")
+}
+
+func TestPprColorNoColor(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("NO_COLOR", "1")
+	o := map[string]any{"key": "value", "num": 1, "ok": true}
+	assert.Equal(gnfmt.Ppr(o), gnfmt.PprColor(o))
+}
+
+func TestPprTo(t *testing.T) {
+	assert := assert.New(t)
+	o := map[string]int{"a": 1}
+
+	var buf bytes.Buffer
+	err := gnfmt.PprTo(&buf, o)
+	assert.Nil(err)
+	assert.Equal(gnfmt.Ppr(o)+"\n", buf.String())
+
+	buf.Reset()
+	err = gnfmt.PprTo(&buf, o, gnfmt.OptPprColor(true))
+	assert.Nil(err)
+	assert.Equal(gnfmt.Ppr(o, gnfmt.OptPprColor(true))+"\n", buf.String())
+
+	err = gnfmt.PprTo(&buf, make(chan int))
+	assert.NotNil(err)
+}
@@ -1,15 +1,166 @@
 package gnfmt
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Colors of JSON elements used by colored pretty prints.
+var (
+	pprKeyColor    = newPprColor(color.FgBlue, color.Bold)
+	pprStringColor = newPprColor(color.FgGreen)
+	pprNumberColor = newPprColor(color.FgCyan)
+	pprBoolColor   = newPprColor(color.FgYellow)
+	pprNullColor   = newPprColor(color.FgMagenta)
+)
+
+// Colors of lines used by PprDiff.
+var (
+	diffDelColor  = newPprColor(color.FgRed)
+	diffAddColor  = newPprColor(color.FgGreen)
+	diffHunkColor = newPprColor(color.FgCyan)
 )
 
-// Ppr is a pretty print of an object
-func Ppr(obj any) string {
-	res, err := json.MarshalIndent(obj, "", "  ")
+// Ppr is a pretty print of an object. Options limit the size of the
+// output, sort keys and hide secret fields.
+func Ppr(obj any, opts ...PprOption) string {
+	res, err := pprint(obj, opts)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	if newPprConfig(opts).color {
+		res = colorizeJSON(res)
+	}
+	return res
+}
+
+// PprColor is a pretty print of an object where keys, strings, numbers,
+// booleans and nulls have distinct colors. Colors are added only if
+// stdout is a terminal and the NO_COLOR environment variable is not set.
+// Use Ppr with OptPprColor(true) to add colors always.
+func PprColor(obj any, opts ...PprOption) string {
+	res, err := pprint(obj, opts)
 	if err != nil {
 		return fmt.Sprintf("Error: %s", err)
 	}
-	return string(res)
+	if useColor(os.Stdout) || newPprConfig(opts).color {
+		res = colorizeJSON(res)
+	}
+	return res
+}
+
+// PprTo writes a pretty print of an object to w followed by a new line.
+// The output is colored if w is a terminal and the NO_COLOR environment
+// variable is not set, or if OptPprColor(true) is given.
+func PprTo(w io.Writer, obj any, opts ...PprOption) error {
+	out, err := pprint(obj, opts)
+	if err != nil {
+		return err
+	}
+	if useColor(w) || newPprConfig(opts).color {
+		out = colorizeJSON(out)
+	}
+	_, err = io.WriteString(w, out+"\n")
+	return err
+}
+
+// useColor checks if colored output should be written to w.
+func useColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	fd := f.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+func newPprColor(attrs ...color.Attribute) *color.Color {
+	res := color.New(attrs...)
+	// the decision about colors is made by the callers, not by the
+	// global settings of the color package.
+	res.EnableColor()
+	return res
+}
+
+// colorizeJSON adds colors to elements of a valid JSON document.
+func colorizeJSON(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '"':
+			end := jsonStringEnd(s, i)
+			str := s[i:end]
+			if isJSONKey(s, end) {
+				b.WriteString(pprKeyColor.Sprint(str))
+			} else {
+				b.WriteString(pprStringColor.Sprint(str))
+			}
+			i = end
+		case strings.HasPrefix(s[i:], "…"):
+			// markers of omitted data are not colored
+			end := i + len("…")
+			if idx := strings.Index(s[end:], " more"); idx >= 0 {
+				end += idx + len(" more")
+			}
+			b.WriteString(s[i:end])
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789+-.eE", s[end]) >= 0 {
+				end++
+			}
+			b.WriteString(pprNumberColor.Sprint(s[i:end]))
+			i = end
+		case strings.HasPrefix(s[i:], "true"):
+			b.WriteString(pprBoolColor.Sprint("true"))
+			i += 4
+		case strings.HasPrefix(s[i:], "false"):
+			b.WriteString(pprBoolColor.Sprint("false"))
+			i += 5
+		case strings.HasPrefix(s[i:], "null"):
+			b.WriteString(pprNullColor.Sprint("null"))
+			i += 4
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// jsonStringEnd returns the index after the closing quote of a JSON
+// string that starts at the index i.
+func jsonStringEnd(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// isJSONKey checks if a string that ends at the index i is followed by
+// a colon.
+func isJSONKey(s string, i int) bool {
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
 }
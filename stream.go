@@ -0,0 +1,57 @@
+package gnfmt
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// encodeStream sends every object from the inputs channel to the
+// encode function until the channel is closed or the context is canceled.
+func encodeStream(
+	ctx context.Context,
+	inputs <-chan any,
+	encode func(any) error,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case input, ok := <-inputs:
+			if !ok {
+				return nil
+			}
+			if err := encode(input); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// decodeStream calls the decode function with new outputs until it
+// returns io.EOF, and sends decoded outputs to the outputs channel.
+func decodeStream(
+	ctx context.Context,
+	newOutput func() any,
+	outputs chan<- any,
+	decode func(any) error,
+) (int, error) {
+	var count int
+	for {
+		output := newOutput()
+		err := decode(output)
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return count, ctx.Err()
+		case outputs <- output:
+			count++
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package gnfmt_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/gnames/gnfmt"
+)
+
+func TestEncodeDecodeTo(t *testing.T) {
+	assert := assert.New(t)
+	encs := []StreamEncoder{
+		GNgob{},
+		GNjson{},
+		GNmsgpack{},
+		GNcbor{},
+		GNcbor{Canonical: true, TimeTag: true},
+		GNyaml{},
+		GNjson{Pretty: true},
+	}
+	for _, e := range encs {
+		var buf bytes.Buffer
+		obj := version{Version: "v10.10.10", Build: "today"}
+		err := e.EncodeTo(&buf, obj)
+		assert.Nil(err)
+
+		var ver version
+		err = e.DecodeFrom(&buf, &ver)
+		assert.Nil(err)
+		assert.Equal(obj, ver)
+	}
+}
+
+func TestEncodeDecodeStream(t *testing.T) {
+	assert := assert.New(t)
+	encs := []StreamEncoder{
+		GNgob{},
+		GNjson{},
+		GNmsgpack{},
+		GNcbor{},
+		GNcbor{Canonical: true, TimeTag: true},
+		GNyaml{},
+		GNjson{Pretty: true},
+	}
+	vers := []version{
+		{Version: "v1.0.0", Build: "one"},
+		{Version: "v1.0.1", Build: "two"},
+		{Version: "v1.0.2", Build: "three"},
+	}
+	ctx := context.Background()
+
+	for _, e := range encs {
+		var buf bytes.Buffer
+		chIn := make(chan any)
+		go func() {
+			defer close(chIn)
+			for _, v := range vers {
+				chIn <- v
+			}
+		}()
+		err := e.EncodeStream(ctx, &buf, chIn)
+		assert.Nil(err)
+
+		chOut := make(chan any)
+		var res []version
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for v := range chOut {
+				res = append(res, *v.(*version))
+			}
+		}()
+
+		newVer := func() any { return &version{} }
+		count, err := e.DecodeStream(ctx, &buf, newVer, chOut)
+		close(chOut)
+		<-done
+		assert.Nil(err)
+		assert.Equal(3, count)
+		assert.Equal(vers, res)
+	}
+}
+
+func TestEncodeStreamCancel(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	chIn := make(chan any)
+	err := GNjson{}.EncodeStream(ctx, &buf, chIn)
+	assert.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,275 @@
+package gnfmt
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// table is a tabular representation of a record: a header with column
+// names and rows of string values.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// column describes a field of a struct that becomes a column in a table.
+// The index is a path of field indices from the top-level struct.
+type column struct {
+	name  string
+	index []int
+}
+
+var (
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+)
+
+// tabulate converts a struct, a pointer to a struct, or a slice/array of
+// structs into a table. Field names are taken from `csv` tags, then from
+// `json` tags, and then from the names of the fields. Nested structs are
+// flattened using dotted column names. It also accepts [][]string, where
+// the first row is a header.
+func tabulate(record any) (table, error) {
+	var res table
+	if record == nil {
+		return res, errors.New("cannot tabulate nil record")
+	}
+
+	if rows, ok := record.([][]string); ok {
+		if len(rows) == 0 {
+			return res, errors.New("cannot tabulate rows without header")
+		}
+		res.header = rows[0]
+		res.rows = rows[1:]
+		return res, nil
+	}
+
+	v := reflect.ValueOf(record)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return res, errors.New("cannot tabulate nil record")
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		cols := columns(v.Type(), "", nil, nil)
+		res.header = columnNames(cols)
+		res.rows = [][]string{rowValues(v, cols)}
+	case reflect.Slice, reflect.Array:
+		elType := v.Type().Elem()
+		if derefType(elType).Kind() != reflect.Struct {
+			return res, fmt.Errorf("cannot tabulate slice of %s", elType)
+		}
+		cols := columns(derefType(elType), "", nil, nil)
+		res.header = columnNames(cols)
+		res.rows = make([][]string, 0, v.Len())
+		for i := range v.Len() {
+			res.rows = append(res.rows, rowValues(v.Index(i), cols))
+		}
+	default:
+		return res, fmt.Errorf("cannot tabulate %s", v.Type())
+	}
+	return res, nil
+}
+
+// columns collects columns from fields of a struct type. Embedded structs
+// without a name in their tags are promoted, other nested structs receive
+// the name of their field as a prefix. The path keeps struct types that
+// are already expanded, a field that refers to one of them again becomes
+// a single column to avoid infinite recursion.
+func columns(
+	t reflect.Type,
+	prefix string,
+	index []int,
+	path []reflect.Type,
+) []column {
+	var res []column
+	path = append(path[:len(path):len(path)], t)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+
+		idx := append(append([]int{}, index...), i)
+		ft := derefType(f.Type)
+		if ft.Kind() == reflect.Struct && !isLeafType(ft) &&
+			!slices.Contains(path, ft) {
+			if f.Anonymous && name == "" {
+				res = append(res, columns(ft, prefix, idx, path)...)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			res = append(res, columns(ft, prefix+name+".", idx, path)...)
+			continue
+		}
+
+		if f.Anonymous && !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		res = append(res, column{name: prefix + name, index: idx})
+	}
+	return res
+}
+
+// fieldName returns the name of a field given in its `csv` or `json` tag.
+// The name is empty if a tag does not provide it. If the field is
+// marked with "-", the second returned value is false.
+func fieldName(f reflect.StructField) (string, bool) {
+	for _, key := range []string{"csv", "json"} {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		if tag == "-" {
+			return "", false
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" {
+			return name, true
+		}
+	}
+	return "", true
+}
+
+func columnNames(cols []column) []string {
+	res := make([]string, len(cols))
+	for i, v := range cols {
+		res[i] = v.name
+	}
+	return res
+}
+
+// rowValues converts values of a struct into strings according to
+// the given columns.
+func rowValues(v reflect.Value, cols []column) []string {
+	res := make([]string, len(cols))
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return res
+		}
+		v = v.Elem()
+	}
+	for i, col := range cols {
+		fv, ok := fieldByIndex(v, col.index)
+		if !ok {
+			continue
+		}
+		res[i] = cellValue(fv)
+	}
+	return res
+}
+
+// fieldByIndex is similar to reflect.Value.FieldByIndex, but it returns
+// false instead of panicking when it meets a nil pointer on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 {
+			for v.Kind() == reflect.Pointer {
+				if v.IsNil() {
+					return v, false
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(idx)
+	}
+	return v, true
+}
+
+// cellValue converts a value into its string representation. Scalar
+// values are converted directly, slices, maps and similar values are
+// converted to compact JSON.
+func cellValue(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		if v.Kind() == reflect.Pointer && isLeafType(v.Type()) {
+			break
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Pointer && v.CanInterface() && isLeafType(v.Type()) {
+		// methods might be defined on a pointer receiver
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+
+	if v.CanInterface() {
+		switch val := v.Interface().(type) {
+		case encoding.TextMarshaler:
+			if txt, err := val.MarshalText(); err == nil {
+				return string(txt)
+			}
+		case fmt.Stringer:
+			return val.String()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+
+	if !v.CanInterface() {
+		return ""
+	}
+	res, err := jsoniter.MarshalToString(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return res
+}
+
+// isLeafType returns true if values of the type know how to represent
+// themselves as text, and should not be flattened.
+func isLeafType(t reflect.Type) bool {
+	if t.Implements(textMarshalerType) || t.Implements(stringerType) {
+		return true
+	}
+	if t.Kind() != reflect.Pointer {
+		pt := reflect.PointerTo(t)
+		return pt.Implements(textMarshalerType) || pt.Implements(stringerType)
+	}
+	return false
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
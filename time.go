@@ -2,7 +2,10 @@ package gnfmt
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func TimeString(secs float64) string {
@@ -56,3 +59,151 @@ func TimeString(secs float64) string {
 
 	return strings.TrimSpace(res)
 }
+
+// FormatISODuration converts a duration into ISO 8601 format, for example
+// "P1DT2H3M4.5S". A day is always 24 hours, years, months and weeks are
+// not used. Zero duration is "PT0S", negative durations start with "-".
+func FormatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	// uint64 keeps the absolute value of the minimal duration
+	val := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		val = -val
+	}
+	b.WriteByte('P')
+
+	day, hour, minute := uint64(Day), uint64(time.Hour), uint64(time.Minute)
+	if days := val / day; days > 0 {
+		b.WriteString(strconv.FormatUint(days, 10) + "D")
+	}
+	val %= day
+	if val == 0 {
+		return b.String()
+	}
+
+	b.WriteByte('T')
+	if hours := val / hour; hours > 0 {
+		b.WriteString(strconv.FormatUint(hours, 10) + "H")
+	}
+	val %= hour
+	if minutes := val / minute; minutes > 0 {
+		b.WriteString(strconv.FormatUint(minutes, 10) + "M")
+	}
+	val %= minute
+	if val > 0 {
+		secs := strconv.FormatUint(val/uint64(time.Second), 10)
+		if nanos := val % uint64(time.Second); nanos > 0 {
+			frac := fmt.Sprintf("%09d", nanos)
+			secs += "." + strings.TrimRight(frac, "0")
+		}
+		b.WriteString(secs + "S")
+	}
+	return b.String()
+}
+
+// ParseISODuration converts an ISO 8601 duration, for example
+// "P1DT2H3M4.5S" or "P2W", into time.Duration. A day is 24 hours and
+// a week is 7 days. Years and months do not have a fixed length, so
+// non-zero values of them are reported as errors. Values can have
+// fractions, with a dot or a comma as the decimal separator. A leading
+// "-" makes the duration negative. Any output of FormatISODuration,
+// including math.MinInt64, is parsed back.
+func ParseISODuration(s string) (time.Duration, error) {
+	orig := s
+	parseErr := func(msg string) error {
+		return fmt.Errorf("cannot parse ISO 8601 duration '%s': %s", orig, msg)
+	}
+
+	var neg bool
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	s = strings.ReplaceAll(strings.ToUpper(s), ",", ".")
+	s, ok := strings.CutPrefix(s, "P")
+	if !ok {
+		return 0, parseErr("it must start with 'P'")
+	}
+	datePart, timePart, hasTime := strings.Cut(s, "T")
+	if datePart == "" && timePart == "" {
+		return 0, parseErr("no values")
+	}
+	if hasTime && timePart == "" {
+		return 0, parseErr("no values after 'T'")
+	}
+
+	type isoUnit struct {
+		designator byte
+		size       time.Duration
+	}
+	dateUnits := []isoUnit{{'Y', 0}, {'M', 0}, {'W', 7 * Day}, {'D', Day}}
+	timeUnits := []isoUnit{{'H', time.Hour}, {'M', time.Minute}, {'S', time.Second}}
+
+	// the magnitude of a negative duration can be one more than
+	// math.MaxInt64, so it is accumulated as uint64.
+	var res uint64
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+	parse := func(part string, units []isoUnit) error {
+		for part != "" {
+			num, rest := splitNumber(part)
+			if num == "" || rest == "" {
+				return parseErr(fmt.Sprintf("invalid value '%s'", part))
+			}
+			idx := -1
+			for i, u := range units {
+				if u.designator == rest[0] {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				return parseErr(fmt.Sprintf("unexpected designator '%c'", rest[0]))
+			}
+
+			u := units[idx]
+			d, err := unitValue(num, max(u.size, 1))
+			if err != nil {
+				return parseErr(err.Error())
+			}
+			if u.size == 0 {
+				// only zero years and months are allowed
+				if strings.Trim(num, "0.") != "" {
+					return parseErr("years and months do not have fixed length")
+				}
+				d = 0
+			}
+			if res > limit-uint64(d) {
+				return parseErr(errDurationRange.Error())
+			}
+			res += uint64(d)
+
+			// every designator can be used once, in order
+			units = units[idx+1:]
+			part = rest[1:]
+		}
+		return nil
+	}
+
+	if err := parse(datePart, dateUnits); err != nil {
+		return 0, err
+	}
+	if err := parse(timePart, timeUnits); err != nil {
+		return 0, err
+	}
+
+	if neg {
+		res = -res
+	}
+	return time.Duration(res), nil
+}
@@ -1,7 +1,9 @@
 package gnfmt_test
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	"github.com/gnames/gnfmt"
 	"github.com/stretchr/testify/assert"
@@ -29,3 +31,84 @@ func TestTimeString(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatISODuration(t *testing.T) {
+	tests := []struct {
+		name string
+		dur  time.Duration
+		str  string
+	}{
+		{"zero", 0, "PT0S"},
+		{"sec", 30 * time.Second, "PT30S"},
+		{"frac", 4500 * time.Millisecond, "PT4.5S"},
+		{"ns", 1, "PT0.000000001S"},
+		{"full", gnfmt.Day + 2*time.Hour + 3*time.Minute + 4500*time.Millisecond,
+			"P1DT2H3M4.5S"},
+		{"days", 14 * gnfmt.Day, "P14D"},
+		{"hour", time.Hour, "PT1H"},
+		{"day min", gnfmt.Day + time.Minute, "P1DT1M"},
+		{"negative", -90 * time.Minute, "-PT1H30M"},
+		{"max", math.MaxInt64, "P106751DT23H47M16.854775807S"},
+		{"min", math.MinInt64, "-P106751DT23H47M16.854775808S"},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			assert.Equal(t, v.str, gnfmt.FormatISODuration(v.dur))
+		})
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		str string
+		dur time.Duration
+	}{
+		{"PT0S", 0},
+		{"P0D", 0},
+		{"P1DT2H3M4.5S", gnfmt.Day + 2*time.Hour + 3*time.Minute + 4500*time.Millisecond},
+		{"P2W", 14 * gnfmt.Day},
+		{"P1W1D", 8 * gnfmt.Day},
+		{"PT1.5H", 90 * time.Minute},
+		{"PT0,25S", 250 * time.Millisecond},
+		{"P0Y0M1D", gnfmt.Day},
+		{"pt36h", 36 * time.Hour},
+		{"-PT1H30M", -90 * time.Minute},
+		{"+PT1M", time.Minute},
+		{"PT0.000000001S", 1},
+		{"P0.0Y0,00M1D", gnfmt.Day},
+		{"P106751DT23H47M16.854775807S", math.MaxInt64},
+		{"-P106751DT23H47M16.854775808S", math.MinInt64},
+	}
+	for _, v := range tests {
+		res, err := gnfmt.ParseISODuration(v.str)
+		assert.Nil(err, v.str)
+		assert.Equal(v.dur, res, v.str)
+	}
+
+	for _, v := range []string{
+		"", "P", "PT", "1D", "P1Y", "P1M", "PT1D", "P1H", "P1DT", "PT1S1M",
+		"PT1M1M", "P1", "PTS", "P1.2.3D", "P200000D", "P1D 2H",
+		"P0.4Y", "P0.0001M", "P0.0.0Y", "P106751DT23H47M16.854775808S",
+		"-P106751DT23H47M16.854775809S", "P106751.99999D", "PT2562047.9H",
+	} {
+		_, err := gnfmt.ParseISODuration(v)
+		assert.NotNil(err, v)
+	}
+	_, err := gnfmt.ParseISODuration("P1Y")
+	assert.ErrorContains(err, "fixed length")
+}
+
+func TestISODurationRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	durs := []time.Duration{
+		0, 1, 999, 1234567890, 7654 * time.Second, -88_000 * time.Second,
+		math.MaxInt64, math.MinInt64 + 1, math.MinInt64,
+	}
+	for _, d := range durs {
+		res, err := gnfmt.ParseISODuration(gnfmt.FormatISODuration(d))
+		assert.Nil(err)
+		assert.Equal(d, res)
+	}
+}